@@ -12,6 +12,8 @@ import (
 	"privacycheck/internal/ruletest"
 	"privacycheck/internal/scanner"
 	"runtime"
+	"strings"
+	"time"
 )
 
 const (
@@ -22,12 +24,35 @@ const (
 	AppLongDesc  = "privacy check base on rules"
 )
 
+// RuleFilterOptions 规则筛选配置 (扫描与 rules list 共用)
+type RuleFilterOptions struct {
+	FilterNames   []string `short:"N" long:"filter-names" description:"按规则名称关键字过滤 (支持多个关键字)"`
+	FilterGroups  []string `short:"G" long:"filter-groups" description:"按规则组名称关键字过滤 (支持多个关键字)"`
+	SensitiveOnly bool     `short:"S" long:"sensitive-only" description:"仅启用标记为敏感信息的规则 (sensitive: true)"`
+	FilterTags    []string `long:"tags" description:"仅启用包含任一标签或分类的规则 (支持多个标签)"`
+	ExcludeTags   []string `long:"exclude-tags" description:"排除包含任一标签或分类的规则 (支持多个标签)"`
+	MinSeverity   string   `long:"min-severity" description:"仅启用严重等级不低于该等级的规则 (未配置severity的规则: sensitive为true视为high, 否则为info)" choice:"info" choice:"low" choice:"medium" choice:"high" choice:"critical"`
+}
+
+// FilterOptions 转换为规则过滤条件
+func (o *RuleFilterOptions) FilterOptions() baserule.FilterOptions {
+	return baserule.FilterOptions{
+		Groups:        o.FilterGroups,
+		Names:         o.FilterNames,
+		SensitiveOnly: o.SensitiveOnly,
+		MinSeverity:   o.MinSeverity,
+		Tags:          o.FilterTags,
+		ExcludeTags:   o.ExcludeTags,
+	}
+}
+
 // Options 表示程序配置
 type Options struct {
 	// 基础配置
-	ProjectName string `short:"n" long:"project-name" description:"项目名称"`
-	ProjectPath string `short:"p" long:"project-path" description:"扫描路径"`
-	RulesFile   string `short:"r" long:"rules" description:"扫描规则文件路径" default:"config.yaml"`
+	ProjectName string   `short:"n" long:"project-name" description:"项目名称"`
+	ProjectPath string   `short:"p" long:"project-path" description:"扫描路径"`
+	RulesFiles  []string `short:"r" long:"rules" description:"扫描规则文件或目录路径 (支持多个, 后加载的同组同名规则覆盖先加载的规则)" default:"config.yaml"`
+	Overrides   []string `long:"overrides" description:"规则覆盖文件路径 (支持多个, 按顺序在规则加载后、验证前应用)"`
 
 	// 过滤文件
 	ExcludePath []string `long:"ep" description:"排除的路径关键字列表 (支持多个关键字 如: /tmp,/cache)"`
@@ -36,53 +61,78 @@ type Options struct {
 	LimitChunk  int      `long:"lc" description:"分块读取阈值 单位:MB (超过此大小时使用分块读取, 0表示禁用, 默认: 5)" default:"5"`
 
 	// 筛选规则
-	FilterNames   []string `short:"N" long:"filter-names" description:"按规则名称关键字过滤 (支持多个关键字)"`
-	FilterGroups  []string `short:"G" long:"filter-groups" description:"按规则组名称关键字过滤 (支持多个关键字)"`
-	SensitiveOnly bool     `short:"S" long:"sensitive-only" description:"仅启用标记为敏感信息的规则 (sensitive: true)"`
+	RuleFilterOptions
 
 	// 性能配置
-	Workers int `short:"w" long:"workers" description:"并发工作线程数 (默认: 8)" default:"8"`
+	Workers      int    `short:"w" long:"workers" description:"并发工作线程数 (默认: 8)" default:"8"`
+	Profile      bool   `long:"profile" description:"记录每条规则的累计匹配耗时、扫描字节数、命中数与超时数, 扫描结束后输出最慢的规则"`
+	ProfileFile  string `long:"profile-file" description:"规则性能统计JSON输出路径 (指定后自动启用 --profile)"`
+	ProfileTop   int    `long:"profile-top" description:"日志中展示的最慢规则数量 (默认: 10)" default:"10"`
+	NoPrefilter  bool   `long:"no-prefilter" description:"关闭关键字预过滤 (默认在执行正则前检查内容是否包含规则的 keywords 或从正则中提取的必需字面量)"`
+	CombineRegex bool   `long:"combine-regex" description:"将 Go 引擎规则按批次合并为一个正则, 一次遍历找出可能匹配的规则后再逐条精确提取 (不改变扫描结果)"`
+
+	// 解码扫描
+	Decode        bool `long:"decode" description:"检测 base64/url/hex 编码内容, 解码后重新应用规则 (结果包含解码链 decode_chain 与原始编码内容的位置)"`
+	DecodeDepth   int  `long:"decode-depth" description:"最大解码层数, 如 base64 内的 url 编码为2层 (默认: 2)" default:"2"`
+	DecodeMaxSize int  `long:"decode-max-size" description:"单个编码内容的最大长度 单位:KB (超过时不解码, 默认: 64)" default:"64"`
+
+	// 规则分析器
+	JWTWordlist string `long:"jwt-wordlist" description:"JWT 分析器的弱签名密钥字典文件 (每行一个密钥, 追加到内置字典)"`
+	KeyFiles    bool   `long:"key-files" description:"解析二进制的 DER 证书/私钥与 PKCS#12 文件 (.der,.cer,.crt,.key,.p8,.pk8,.p12,.pfx), 输出类型、密钥长度、是否加密、证书信息与公钥指纹"`
 
 	// 输出配置
 	OutputFile    string   `short:"o" long:"output-file" description:"输出文件路径 (默认: {项目名称}.{格式})"`
 	OutputGroup   bool     `short:"g" long:"output-group" description:"按规则组分别输出到不同文件"`
-	OutputKeys    []string `short:"O" long:"output-keys" description:"指定输出字段 (可选: file,group,rule_name,match,context,position,line_number,sensitive)"`
+	OutputKeys    []string `short:"O" long:"output-keys" description:"指定输出字段 (可选: file,group,rule_id,rule_name,match,context,position,line_number,sensitive,severity,confidence,category,tags,description,remediation,references,entropy,matched_rules,decode_chain,encoded,encoded_size,analysis)"`
 	OutputFormat  string   `short:"f" long:"output-format" description:"输出文件格式" choice:"json" choice:"csv" default:"json"`
 	FormatResults bool     `short:"F" long:"format-results" description:"格式化输出结果，清理多余的引号和空格 (默认: 启用)"`
 	BlockMatches  []string `short:"b" long:"block-matches" description:"匹配结果黑名单过滤关键字列表"`
+	FilterFile    string   `long:"filter-file" description:"正则结果过滤器配置文件 (YAML)"`
+	FilterInclude []string `long:"filter-include" description:"仅保留命中表达式的结果 (格式: field:regex, 多条件使用两侧带空格的 && 或 || 连接, 只支持单层与/或且不能混用, 正则本身含两侧带空格的 && 或 || 时请使用 --filter-file; 字段: file,group,rule_id,rule_name,match,context,severity,category,tags)"`
+	FilterExclude []string `long:"filter-exclude" description:"排除命中表达式的结果 (格式同 --filter-include, 如: file:_test\\.go$ && context:mock)"`
+	DedupeFile    bool     `long:"dedupe-file" description:"同一文件中相同规则与匹配值的结果只保留第一次出现"`
+	Aggregate     bool     `long:"aggregate" description:"按规则与规范化匹配值聚合输出, 包含出现次数、涉及文件与首次/末次出现位置 (忽略 --output-keys)"`
+	Overlap       string   `long:"overlap" description:"同一文件中匹配位置重叠的结果处理方式: none 保留全部, priority 只保留优先级最高的规则, merge 合并为一条结果并在 matched_rules 中列出全部规则" choice:"none" choice:"priority" choice:"merge" default:"none"`
 
 	// 自动化启用缓存
-	Cached    bool   `long:"cached" description:"enable scan cacher"`
-	scanCache string `long:"scan-cache" description:"customized scan cache file path（default <project>.<hash>.<engine>.cache）"`
+	Cached    bool   `long:"cached" description:"启用扫描结果缓存, 再次扫描时只处理内容变化的文件 (规则变化时缓存自动失效)"`
+	ScanCache string `long:"scan-cache" description:"扫描缓存文件路径 (指定后自动启用 --cached, 默认: <项目名称>.<hash>.<程序名>.cache)"`
+
+	// 断点续扫
+	Journal     bool   `long:"journal" description:"记录扫描进度日志, 扫描被中断后可以使用 --resume 恢复 (日志包含匹配结果, 扫描结果输出后自动删除)"`
+	JournalFile string `long:"journal-file" description:"扫描进度日志路径 (指定后自动启用 --journal, 默认: <项目名称>.<hash>.<程序名>.journal)"`
+	Resume      bool   `long:"resume" description:"从扫描进度日志恢复被中断的扫描, 跳过已完成且未修改的文件并合并之前的结果 (自动启用 --journal)"`
 
 	// 日志配置
 	LogFile    string `long:"lf" description:"日志文件 (为空则不写入文件)" default:""`
 	LogLevel   string `long:"ll" description:"日志级别 (debug/info/warn/error)" choice:"debug" choice:"info" choice:"warn" choice:"error" default:"info"`
 	LogConsole string `long:"cf" description:"控制台日志格式 (T=时间,L=级别,C=调用者,M=消息,F=函数,off=关闭)" default:"TLM"`
 
-	Test    bool `long:"test" description:"test all rules and generate test report"`
-	Version bool `short:"v" long:"version" description:"display the program version and exit"`
+	Test       bool   `long:"test" description:"test all rules and generate test report"`
+	TestBudget int    `long:"test-budget" description:"规则测试中单条规则处理单个对抗输入的耗时预算 单位:毫秒 (超出视为慢规则)" default:"1000"`
+	TestJSON   string `long:"test-json" description:"规则测试结果输出为JSON文件 (为空则不输出)"`
+	TestJUnit  string `long:"test-junit" description:"规则测试结果输出为JUnit XML文件 (为空则不输出, 可供CI解析)"`
+	TestCorpus string `long:"test-corpus" description:"规则测试中对比 Go 与 Java 引擎匹配结果时额外使用的语料目录"`
+	Version    bool   `short:"v" long:"version" description:"display the program version and exit"`
 }
 
 func main() {
+	// 规则管理子命令
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		os.Exit(runRulesCommand(os.Args[2:]))
+	}
+
 	// 初始化命令行输入配置
 	opts, _ := InitOptionsArgs(1)
 
 	// 加载规则配置
-	rulesConfig, err := baserule.LoadRulesYaml(opts.RulesFile)
-	if err != nil {
-		logging.Fatalf("Loading the rule config failed: %v", err)
-	}
-
-	if len(rulesConfig.Rules) == 0 {
-		logging.Fatalf("rule config is empty, please check your rules")
-	}
-
-	logging.Infof("Load rule file rules group: %d", len(rulesConfig.Rules))
+	rulesConfig := loadRulesConfig(opts.RulesFiles, opts.Overrides)
 
 	// 检查是否为测试模式
 	if opts.Test {
-		ruletest.RunRuleTest(opts.RulesFile, rulesConfig.Rules)
+		if !ruletest.RunRuleTest(rulesConfig.Files[0], rulesConfig.Rules, opts.testOptions()) {
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -92,7 +142,7 @@ func main() {
 	}
 
 	// 过滤规则
-	filteredRules := rulesConfig.FilterRules(opts.FilterGroups, opts.FilterNames, opts.SensitiveOnly)
+	filteredRules := rulesConfig.FilterRules(opts.FilterOptions())
 	ruleCount := filteredRules.CountRules()
 
 	logging.Infof("filtered rules group: %d, rules count:%d", len(filteredRules), ruleCount)
@@ -106,11 +156,35 @@ func main() {
 
 	// 创建扫描器
 	scannerConfig := &scanner.ScanConfig{
-		Workers:     opts.Workers,
-		ProjectName: opts.ProjectName,
-		ProjectPath: opts.ProjectPath,
-		CacheFile:   opts.scanCache,
-		ChunkLimit:  opts.LimitChunk,
+		Workers:      opts.Workers,
+		ProjectName:  opts.ProjectName,
+		ProjectPath:  opts.ProjectPath,
+		CacheFile:    opts.ScanCache,
+		JournalFile:  opts.JournalFile,
+		Resume:       opts.Resume,
+		ChunkLimit:   opts.LimitChunk,
+		Profile:      opts.Profile,
+		ProfileFile:  opts.ProfileFile,
+		ProfileTop:   opts.ProfileTop,
+		NoPrefilter:  opts.NoPrefilter,
+		CombineRegex: opts.CombineRegex,
+		Overlap:      opts.Overlap,
+		Decode:       opts.decodeOptions(),
+		KeyFiles:     opts.KeyFiles,
+	}
+	if opts.JWTWordlist != "" {
+		wordlist, err := loadWordlist(opts.JWTWordlist)
+		if err != nil {
+			logging.Fatalf("failed to load JWT wordlist: %v", err)
+		}
+		logging.Infof("loaded %d weak keys from JWT wordlist %s", len(wordlist), opts.JWTWordlist)
+		scannerConfig.JWTWordlist = wordlist
+	}
+
+	// 加载结果过滤器, 在扫描前发现配置错误
+	outputProcessor := newOutputConfig(opts)
+	if err := outputProcessor.LoadFilters(); err != nil {
+		logging.Fatalf("failed to load result filters: %v", err)
 	}
 
 	// 获取待扫描文件 - 使用 fileutils 直接进行过滤
@@ -134,16 +208,84 @@ func main() {
 
 	// 处理输出
 	if len(results) > 0 {
-		outputProcessor := newOutputConfig(opts)
 		if err := outputProcessor.ProcessResults(results); err != nil {
 			logging.Fatalf("failed to output results: %v", err)
 		}
 	} else {
 		logging.Info("no sensitive information found")
 	}
+	instance.FinishJournal()
 	logging.Info("program execution completed")
 }
 
+// loadRulesConfig 加载并合并规则文件, 输出同名规则的覆盖情况, 然后应用规则覆盖文件
+func loadRulesConfig(rulesFiles, overrideFiles []string) *baserule.RuleConfig {
+	rulesConfig, conflicts, err := baserule.LoadRules(rulesFiles)
+	if err != nil {
+		logging.Fatalf("Loading the rule config failed: %v", err)
+	}
+
+	for _, conflict := range conflicts {
+		logging.Warnf("rule conflict: %s", conflict)
+	}
+
+	for _, overrideFile := range overrideFiles {
+		overrides, err := baserule.LoadRuleOverrides(overrideFile)
+		if err != nil {
+			logging.Fatalf("Loading the rule overrides failed: %v", err)
+		}
+		if err := rulesConfig.ApplyOverrides(overrides); err != nil {
+			logging.Fatalf("Applying the rule overrides %s failed: %v", overrideFile, err)
+		}
+	}
+
+	if len(rulesConfig.Rules) == 0 {
+		logging.Fatalf("rule config is empty, please check your rules")
+	}
+
+	logging.Infof("Load rule file rules group: %d", len(rulesConfig.Rules))
+	return rulesConfig
+}
+
+// testOptions 从命令行配置创建规则测试配置
+func (o *Options) testOptions() ruletest.TestOptions {
+	return ruletest.TestOptions{
+		Budget:    time.Duration(o.TestBudget) * time.Millisecond,
+		JSONFile:  o.TestJSON,
+		JUnitFile: o.TestJUnit,
+		CorpusDir: o.TestCorpus,
+	}
+}
+
+// decodeOptions 转换为解码扫描配置, 未启用时返回nil
+func (o *Options) decodeOptions() *scanner.DecodeOptions {
+	if !o.Decode {
+		return nil
+	}
+	return &scanner.DecodeOptions{
+		Depth:   o.DecodeDepth,
+		MaxSize: o.DecodeMaxSize * 1024,
+	}
+}
+
+// loadWordlist 读取字典文件, 每行一个词, 忽略空行与 # 开头的注释行
+func loadWordlist(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(data), "\n") {
+		word := strings.TrimRight(line, "\r")
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, nil
+}
+
 // newOutputConfig 从命令行配置创建输出配置
 func newOutputConfig(cmdConfig *Options) *output.Output {
 	return &output.Output{
@@ -153,6 +295,11 @@ func newOutputConfig(cmdConfig *Options) *output.Output {
 		OutputFormat:  cmdConfig.OutputFormat,
 		FormatResults: cmdConfig.FormatResults,
 		BlockMatches:  cmdConfig.BlockMatches,
+		FilterFile:    cmdConfig.FilterFile,
+		FilterInclude: cmdConfig.FilterInclude,
+		FilterExclude: cmdConfig.FilterExclude,
+		DedupeFile:    cmdConfig.DedupeFile,
+		Aggregate:     cmdConfig.Aggregate,
 		ProjectName:   cmdConfig.ProjectName,
 	}
 }
@@ -197,14 +344,18 @@ func InitOptionsArgs(minimumParams int) (*Options, *flags.Parser) {
 	}
 	defer logging.Sync()
 
-	// 如果配置文件不存在，创建默认配置文件
-	if !utils.FileExists(opts.RulesFile) {
-		logging.Warnf("config file %s not exist, will create default config ...", opts.RulesFile)
-		if err := baserule.CreateDefaultConfig(opts.RulesFile); err != nil {
-			logging.Errorf("create default config %s error:%v", opts.RulesFile, err)
+	// 如果仅指定了一个配置文件且不存在，创建默认配置文件(目录与通配符路径不创建, 加载时报错)
+	if len(opts.RulesFiles) == 1 {
+		rulesFile := opts.RulesFiles[0]
+		isPlainFile := !strings.ContainsAny(rulesFile, "*?[") && !strings.HasSuffix(rulesFile, "/") && !strings.HasSuffix(rulesFile, string(os.PathSeparator))
+		if exists, _, _ := utils.PathExists(rulesFile); !exists && isPlainFile {
+			logging.Warnf("config file %s not exist, will create default config ...", rulesFile)
+			if err := baserule.CreateDefaultConfig(rulesFile); err != nil {
+				logging.Errorf("create default config %s error:%v", rulesFile, err)
+			}
+			logging.Infof("default config file has been created: %s", rulesFile)
+			os.Exit(0)
 		}
-		logging.Infof("default config file has been created: %s", opts.RulesFile)
-		os.Exit(0)
 	}
 
 	// 处理项目路径
@@ -229,26 +380,45 @@ func InitOptionsArgs(minimumParams int) (*Options, *flags.Parser) {
 	// 验证输出键的有效性
 	if len(opts.OutputKeys) > 0 {
 		allowedKeys := map[string]bool{
-			"file":        true,
-			"group":       true,
-			"rule_name":   true,
-			"match":       true,
-			"context":     true,
-			"position":    true,
-			"line_number": true,
-			"sensitive":   true,
+			"file":          true,
+			"group":         true,
+			"rule_id":       true,
+			"rule_name":     true,
+			"match":         true,
+			"context":       true,
+			"position":      true,
+			"line_number":   true,
+			"sensitive":     true,
+			"severity":      true,
+			"confidence":    true,
+			"category":      true,
+			"tags":          true,
+			"description":   true,
+			"remediation":   true,
+			"references":    true,
+			"entropy":       true,
+			"matched_rules": true,
+			"decode_chain":  true,
+			"encoded":       true,
+			"encoded_size":  true,
+			"analysis":      true,
 		}
 
 		for _, key := range opts.OutputKeys {
 			if !allowedKeys[key] {
-				logging.Fatalf("invalid output key: %s, allowed keys: file, group, rule_name, match, context, position, line_number, sensitive", key)
+				logging.Fatalf("invalid output key: %s, allowed keys: file, group, rule_id, rule_name, match, context, position, line_number, sensitive, severity, confidence, category, tags, description, remediation, references, entropy, matched_rules, decode_chain, encoded, encoded_size, analysis", key)
 			}
 		}
 	}
 
 	// 配置缓存功能
-	if opts.Cached && opts.scanCache == "" {
-		opts.scanCache = utils.GenProjectFileName(opts.ProjectName, opts.ProjectPath, AppName, "cache")
+	if opts.Cached && opts.ScanCache == "" {
+		opts.ScanCache = utils.GenProjectFileName(opts.ProjectName, opts.ProjectPath, AppName, "cache")
+	}
+
+	// 配置扫描进度日志
+	if (opts.Journal || opts.Resume) && opts.JournalFile == "" {
+		opts.JournalFile = utils.GenProjectFileName(opts.ProjectName, opts.ProjectPath, AppName, "journal")
 	}
 
 	// 验证工作线程数
@@ -258,15 +428,16 @@ func InitOptionsArgs(minimumParams int) (*Options, *flags.Parser) {
 
 	// 检查是否为测试模式
 	if opts.Test {
-		if rulesConfig, err := baserule.LoadRulesYaml(opts.RulesFile); err == nil {
-			ruletest.RunRuleTest(opts.RulesFile, rulesConfig.Rules)
+		rulesConfig := loadRulesConfig(opts.RulesFiles, opts.Overrides)
+		if !ruletest.RunRuleTest(rulesConfig.Files[0], rulesConfig.Rules, opts.testOptions()) {
+			os.Exit(1)
 		}
 		os.Exit(0)
 	}
 
 	logging.Infof("ProjectName: %s", opts.ProjectName)
 	logging.Infof("ProjectPath: %s", opts.ProjectPath)
-	logging.Infof("RulesFile: %s", opts.RulesFile)
+	logging.Infof("RulesFiles: %s", strings.Join(opts.RulesFiles, ", "))
 	logging.Infof("Workers: %d", opts.Workers)
 	logging.Infof("Output: %s", opts.OutputFile)
 
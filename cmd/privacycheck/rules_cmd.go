@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/ruleconv"
+)
+
+// RulesCommands 规则管理子命令
+type RulesCommands struct {
+	Convert ConvertCommand `command:"convert" description:"在 PrivacyCheck、HAE 与 gitleaks 规则格式之间转换"`
+	List    ListCommand    `command:"list" description:"列出筛选后实际生效的规则"`
+	Show    ShowCommand    `command:"show" description:"显示规则的完整定义及实际使用的引擎"`
+	Eval    EvalCommand    `command:"eval" description:"在标注语料上评估规则的准确率与召回率"`
+}
+
+// RuleSourceOptions 规则来源配置 (与扫描参数一致)
+type RuleSourceOptions struct {
+	RulesFiles []string `short:"r" long:"rules" description:"扫描规则文件或目录路径 (支持多个)" default:"config.yaml"`
+	Overrides  []string `long:"overrides" description:"规则覆盖文件路径 (支持多个)"`
+}
+
+// ListCommand 列出生效规则
+type ListCommand struct {
+	RuleSourceOptions
+	RuleFilterOptions
+
+	Format string `short:"f" long:"format" description:"输出格式" choice:"table" choice:"json" choice:"csv" default:"table"`
+	Output string `short:"o" long:"output" description:"输出文件路径 (为空则输出到标准输出)"`
+}
+
+// ShowCommand 显示规则详情
+type ShowCommand struct {
+	RuleSourceOptions
+
+	Args struct {
+		Name string `positional-arg-name:"name" description:"规则名称或规则ID"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// EvalCommand 规则准确率与召回率评估
+type EvalCommand struct {
+	RuleSourceOptions
+	RuleFilterOptions
+
+	Corpus      string `short:"c" long:"corpus" description:"标注语料目录" required:"true"`
+	Annotations string `short:"a" long:"annotations" description:"标注文件 (YAML/JSON, 文件路径相对于语料目录)" required:"true"`
+	Baseline    string `short:"b" long:"baseline" description:"之前保存的评估结果JSON文件, 用于对比规则指标变化"`
+	Output      string `short:"o" long:"output" description:"评估结果JSON输出路径 (可作为下次评估的基线)"`
+	Format      string `short:"f" long:"format" description:"标准输出格式" choice:"table" choice:"json" default:"table"`
+	Workers     int    `short:"w" long:"workers" description:"扫描并发数" default:"4"`
+}
+
+// ConvertCommand 规则格式转换
+type ConvertCommand struct {
+	From   string   `long:"from" description:"源规则格式" choice:"privacycheck" choice:"hae" choice:"gitleaks" default:"privacycheck"`
+	To     string   `long:"to" description:"目标规则格式" choice:"privacycheck" choice:"hae" choice:"gitleaks" default:"privacycheck"`
+	Inputs []string `short:"i" long:"input" description:"源规则文件或目录 (支持多个)" required:"true"`
+	Output string   `short:"o" long:"output" description:"输出文件路径 (为空则输出到标准输出)"`
+	Group  string   `long:"group" description:"gitleaks 规则导入后的规则组名称" default:"Gitleaks"`
+}
+
+// Execute 执行规则格式转换, 无法表示的内容输出到标准错误
+func (c *ConvertCommand) Execute(_ []string) error {
+	config, loadIssues, err := ruleconv.Load(c.From, c.Inputs, c.Group)
+	if err != nil {
+		return err
+	}
+
+	data, exportIssues, err := ruleconv.Export(config, c.To)
+	if err != nil {
+		return err
+	}
+
+	issues := append(loadIssues, exportIssues...)
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "[!] %s\n", issue)
+	}
+
+	if c.Output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := utils.SaveToFile(c.Output, data); err != nil {
+		return fmt.Errorf("failed to write converted rules: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "converted %s rules to %s: %s (%d issues)\n", c.From, c.To, c.Output, len(issues))
+	return nil
+}
+
+// runRulesCommand 解析并执行 rules 子命令, 返回进程退出码
+func runRulesCommand(args []string) int {
+	commands := &RulesCommands{}
+	parser := flags.NewParser(commands, flags.Default)
+	parser.Name = AppName + " rules"
+
+	// 子命令的结果输出到标准输出, 日志仅保留警告与错误
+	logCfg := logging.NewLogConfig("warn", "", "TLM")
+	if err := logging.InitLogger(logCfg); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		return 1
+	}
+	defer logging.Sync()
+
+	// 解析错误与子命令执行错误均已由解析器输出
+	if _, err := parser.ParseArgs(args); err != nil {
+		var flagsErr *flags.Error
+		if errors.As(err, &flagsErr) && errors.Is(flagsErr.Type, flags.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	return 0
+}
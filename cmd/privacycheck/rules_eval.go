@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/ruleeval"
+)
+
+// Execute 扫描标注语料并输出每条规则的 TP/FP/FN、准确率与召回率, 指定基线时输出指标变化
+func (c *EvalCommand) Execute(_ []string) error {
+	rulesConfig := loadRulesConfig(c.RulesFiles, c.Overrides)
+	if err := rulesConfig.ValidateRules(); err != nil {
+		return fmt.Errorf("rule content validation failed: %w", err)
+	}
+	filteredRules := rulesConfig.FilterRules(c.FilterOptions())
+
+	evaluation, err := ruleeval.Evaluate(filteredRules, c.Corpus, c.Annotations, utils.MaxNum(c.Workers, 1))
+	if err != nil {
+		return err
+	}
+	if c.Baseline != "" {
+		baseline, err := ruleeval.LoadEvaluation(c.Baseline)
+		if err != nil {
+			return err
+		}
+		evaluation.Changes = ruleeval.Diff(baseline, evaluation)
+	}
+
+	if c.Output != "" {
+		if err := utils.SaveJSON(c.Output, evaluation); err != nil {
+			return fmt.Errorf("failed to write evaluation: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "evaluation has been written to %s\n", c.Output)
+	}
+
+	var buf bytes.Buffer
+	if c.Format == "json" {
+		data, err := json.MarshalIndent(evaluation, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	} else {
+		writeEvaluationTable(&buf, evaluation)
+	}
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
+
+// formatRatio 格式化准确率或召回率, 不可计算时输出 "-"
+func formatRatio(ratio *float64) string {
+	if ratio == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*ratio*100, 'f', 1, 64) + "%"
+}
+
+// metricsRow 将规则指标转换为表格行
+func metricsRow(metric ruleeval.RuleMetrics) string {
+	return strings.Join([]string{
+		metric.Rule,
+		strconv.Itoa(metric.TruePositives),
+		strconv.Itoa(metric.FalsePositives),
+		strconv.Itoa(metric.FalseNegatives),
+		formatRatio(metric.Precision),
+		formatRatio(metric.Recall),
+	}, "\t")
+}
+
+// writeEvaluationTable 以表格输出评估结果、误报漏报明细与基线对比
+func writeEvaluationTable(buf *bytes.Buffer, evaluation *ruleeval.Evaluation) {
+	writer := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "RULE\tTP\tFP\tFN\tPRECISION\tRECALL")
+	for _, metric := range evaluation.Rules {
+		fmt.Fprintln(writer, metricsRow(metric))
+	}
+	fmt.Fprintln(writer, metricsRow(evaluation.Total))
+	writer.Flush()
+	fmt.Fprintf(buf, "\n%d files, %d rules, %d ignored annotations\n", evaluation.Files, len(evaluation.Rules), evaluation.Ignored)
+
+	if len(evaluation.FalsePositives) > 0 {
+		fmt.Fprintf(buf, "\nfalse positives (%d):\n", len(evaluation.FalsePositives))
+		for _, finding := range evaluation.FalsePositives {
+			fmt.Fprintf(buf, "  [%s] %s:%d %q\n", finding.Rule, finding.File, finding.Line, finding.Match)
+		}
+	}
+	if len(evaluation.FalseNegatives) > 0 {
+		fmt.Fprintf(buf, "\nfalse negatives (%d):\n", len(evaluation.FalseNegatives))
+		for _, finding := range evaluation.FalseNegatives {
+			fmt.Fprintf(buf, "  [%s] %s:%d %q\n", finding.Rule, finding.File, finding.Line, finding.Match)
+		}
+	}
+
+	if evaluation.Changes != nil {
+		fmt.Fprintf(buf, "\nchanges against baseline (%d):\n", len(evaluation.Changes))
+		writer = tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "RULE\tCHANGE\tTP\tFP\tFN\tPRECISION\tRECALL")
+		for _, change := range evaluation.Changes {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", change.Rule, change.Change,
+				diffInt(change.Before, change.After, func(m *ruleeval.RuleMetrics) int { return m.TruePositives }),
+				diffInt(change.Before, change.After, func(m *ruleeval.RuleMetrics) int { return m.FalsePositives }),
+				diffInt(change.Before, change.After, func(m *ruleeval.RuleMetrics) int { return m.FalseNegatives }),
+				diffRatio(change.Before, change.After, func(m *ruleeval.RuleMetrics) *float64 { return m.Precision }),
+				diffRatio(change.Before, change.After, func(m *ruleeval.RuleMetrics) *float64 { return m.Recall }))
+		}
+		writer.Flush()
+	}
+}
+
+// diffInt 格式化计数变化, 如 "3 -> 5"
+func diffInt(before, after *ruleeval.RuleMetrics, value func(*ruleeval.RuleMetrics) int) string {
+	beforeText, afterText := "-", "-"
+	if before != nil {
+		beforeText = strconv.Itoa(value(before))
+	}
+	if after != nil {
+		afterText = strconv.Itoa(value(after))
+	}
+	return beforeText + " -> " + afterText
+}
+
+// diffRatio 格式化比率变化, 如 "50.0% -> 75.0%"
+func diffRatio(before, after *ruleeval.RuleMetrics, value func(*ruleeval.RuleMetrics) *float64) string {
+	beforeText, afterText := "-", "-"
+	if before != nil {
+		beforeText = formatRatio(value(before))
+	}
+	if after != nil {
+		afterText = formatRatio(value(after))
+	}
+	return beforeText + " -> " + afterText
+}
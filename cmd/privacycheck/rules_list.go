@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/winezer0/xutils/utils"
+	"gopkg.in/yaml.v3"
+	"privacycheck/internal/baserule"
+)
+
+// ruleInfoHeaders 规则清单表头
+var ruleInfoHeaders = []string{"group", "id", "name", "engine", "severity", "confidence", "category", "tags", "sensitive", "has_sample"}
+
+// ruleInfoRow 将规则摘要转换为表格行
+func ruleInfoRow(info baserule.RuleInfo) []string {
+	return []string{
+		info.Group,
+		info.ID,
+		info.Name,
+		info.Engine,
+		info.Severity,
+		info.Confidence,
+		info.Category,
+		strings.Join(info.Tags, ";"),
+		strconv.FormatBool(info.Sensitive),
+		strconv.FormatBool(info.HasSample),
+	}
+}
+
+// Execute 列出筛选后实际生效的规则, 规则验证失败时返回错误
+func (c *ListCommand) Execute(_ []string) error {
+	rulesConfig := loadRulesConfig(c.RulesFiles, c.Overrides)
+	if err := rulesConfig.ValidateRules(); err != nil {
+		return fmt.Errorf("rule content validation failed: %w", err)
+	}
+	infos := rulesConfig.DescribeRules(rulesConfig.FilterRules(c.FilterOptions()))
+
+	var buf bytes.Buffer
+	switch c.Format {
+	case "json":
+		data, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	case "csv":
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write(ruleInfoHeaders)
+		for _, info := range infos {
+			_ = writer.Write(ruleInfoRow(info))
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	default:
+		writer := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(ruleInfoHeaders, "\t")))
+		for _, info := range infos {
+			fmt.Fprintln(writer, strings.Join(ruleInfoRow(info), "\t"))
+		}
+		writer.Flush()
+		fmt.Fprintf(&buf, "\n%d rules\n", len(infos))
+	}
+
+	if c.Output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := utils.SaveToFile(c.Output, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write rule list: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "rule list (%d rules) has been written to %s\n", len(infos), c.Output)
+	return nil
+}
+
+// ruleDetail 规则详情
+type ruleDetail struct {
+	Group       string        `yaml:"group"`
+	Engine      string        `yaml:"engine_resolved"`         // 扫描时实际使用的引擎
+	ScanPattern string        `yaml:"scan_pattern"`            // 扫描时实际编译的正则
+	CompileErr  string        `yaml:"compile_error,omitempty"` // 编译错误
+	Keywords    []string      `yaml:"keywords_resolved"`       // 预过滤关键字(配置或从 Go 引擎规则中自动提取, 为空时不预过滤)
+	Rule        baserule.Rule `yaml:"rule"`
+}
+
+// Execute 按名称或ID显示规则完整定义(包含未启用的规则)
+func (c *ShowCommand) Execute(_ []string) error {
+	rulesConfig := loadRulesConfig(c.RulesFiles, c.Overrides)
+	found := rulesConfig.FindRules(c.Args.Name)
+	if len(found) == 0 {
+		return fmt.Errorf("rule not found: %s", c.Args.Name)
+	}
+
+	var details []ruleDetail
+	for _, group := range found {
+		for _, rule := range group.Rule {
+			detail := ruleDetail{Group: group.Group, ScanPattern: rule.ScanPattern(), Rule: rule}
+			if matcher, err := rule.CompileMatcher(); err != nil {
+				detail.Engine = "invalid"
+				detail.CompileErr = err.Error()
+			} else {
+				detail.Engine = string(baserule.MatcherEngine(matcher))
+				detail.Keywords = rule.PrefilterKeywords(baserule.MatcherEngine(matcher))
+			}
+			details = append(details, detail)
+		}
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	for _, detail := range details {
+		if err := encoder.Encode(detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package baserule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Allowlist 规则白名单, 命中白名单的结果会被忽略
+type Allowlist struct {
+	Regexes   []string `yaml:"regexes,omitempty" json:"regexes,omitempty"`     // 匹配提取值的正则(Go RE2语法)
+	Paths     []string `yaml:"paths,omitempty" json:"paths,omitempty"`         // 匹配文件路径的正则(Go RE2语法)
+	Stopwords []string `yaml:"stopwords,omitempty" json:"stopwords,omitempty"` // 提取值包含任一关键字时忽略(忽略大小写)
+}
+
+// IsEmpty 判断白名单是否为空
+func (a *Allowlist) IsEmpty() bool {
+	return len(a.Regexes) == 0 && len(a.Paths) == 0 && len(a.Stopwords) == 0
+}
+
+// Append 追加白名单条目
+func (a *Allowlist) Append(other Allowlist) {
+	a.Regexes = append(a.Regexes, other.Regexes...)
+	a.Paths = append(a.Paths, other.Paths...)
+	a.Stopwords = append(a.Stopwords, other.Stopwords...)
+}
+
+// CompiledAllowlist 预编译的白名单
+type CompiledAllowlist struct {
+	regexes   []*regexp.Regexp
+	paths     []*regexp.Regexp
+	stopwords []string
+}
+
+// Compile 编译白名单中的正则表达式
+func (a *Allowlist) Compile() (*CompiledAllowlist, error) {
+	compiled := &CompiledAllowlist{}
+
+	for _, pattern := range a.Regexes {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist regex [%s] compile error: %w", pattern, err)
+		}
+		compiled.regexes = append(compiled.regexes, regex)
+	}
+
+	for _, pattern := range a.Paths {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist path [%s] compile error: %w", pattern, err)
+		}
+		compiled.paths = append(compiled.paths, regex)
+	}
+
+	for _, stopword := range a.Stopwords {
+		if stopword = strings.ToLower(strings.TrimSpace(stopword)); stopword != "" {
+			compiled.stopwords = append(compiled.stopwords, stopword)
+		}
+	}
+
+	return compiled, nil
+}
+
+// Allowed 判断提取值或文件路径是否命中白名单
+func (c *CompiledAllowlist) Allowed(value, filePath string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, regex := range c.paths {
+		if filePath != "" && regex.MatchString(filePath) {
+			return true
+		}
+	}
+
+	for _, regex := range c.regexes {
+		if regex.MatchString(value) {
+			return true
+		}
+	}
+
+	lowerValue := strings.ToLower(value)
+	for _, stopword := range c.stopwords {
+		if strings.Contains(lowerValue, stopword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package baserule
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// 内置分析器名称
+const (
+	AnalyzerJWT = "jwt" // JSON Web Token 头部与载荷分析
+	AnalyzerKey = "key" // PEM/OpenSSH 私钥、公钥与证书分析
+)
+
+// Finding 分析器对提取值的分析结果, 作为独立的结果输出
+type Finding struct {
+	Type        string // 分析结果类型(如 jwt.algorithm)
+	Value       string // 分析结果内容
+	Severity    string // 严重等级, 为空时使用规则的严重等级
+	Category    string // 数据分类, 为空时使用规则的分类
+	Description string // 分析结果说明
+}
+
+// AnalyzerContext 分析器的运行配置
+type AnalyzerContext struct {
+	Now      time.Time // 判断过期时间的当前时间
+	Wordlist []string  // 弱密钥字典(追加到内置字典)
+}
+
+// AnalyzerFunc 分析提取值, 返回额外的分析结果; 提取值无法解析时返回nil
+type AnalyzerFunc func(value string, ctx *AnalyzerContext) []Finding
+
+// analyzers 内置分析器
+var analyzers = map[string]AnalyzerFunc{
+	AnalyzerJWT: AnalyzeJWT,
+	AnalyzerKey: AnalyzeKey,
+}
+
+// AnalyzerNames 返回所有内置分析器名称
+func AnalyzerNames() []string {
+	names := make([]string, 0, len(analyzers))
+	for name := range analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsAnalyzerSupported 判断分析器是否存在
+func IsAnalyzerSupported(name string) bool {
+	_, ok := analyzers[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// Analyze 使用规则配置的分析器分析提取值, 未配置分析器时返回nil
+func (r *Rule) Analyze(value string, ctx *AnalyzerContext) []Finding {
+	if r.Analyzer == "" {
+		return nil
+	}
+	analyzer, ok := analyzers[strings.ToLower(strings.TrimSpace(r.Analyzer))]
+	if !ok {
+		return nil
+	}
+	if ctx == nil {
+		ctx = &AnalyzerContext{}
+	}
+	if ctx.Now.IsZero() {
+		ctx = &AnalyzerContext{Now: time.Now(), Wordlist: ctx.Wordlist}
+	}
+	return analyzer(value, ctx)
+}
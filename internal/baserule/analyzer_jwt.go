@@ -0,0 +1,178 @@
+package baserule
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jwtWeakKeys 内置的常见弱 HMAC 签名密钥(包括文档与示例代码中的默认值)
+var jwtWeakKeys = []string{
+	"", "secret", "secretkey", "secret_key", "secret-key", "mysecret", "my_secret", "supersecret", "s3cr3t",
+	"jwt", "jwtsecret", "jwt_secret", "jwt-secret", "jwtkey", "jwt_key", "key", "private", "token",
+	"password", "passw0rd", "123456", "12345678", "123456789", "changeme", "change_me", "default",
+	"admin", "test", "qwerty", "hs256", "your-256-bit-secret", "your-384-bit-secret", "your-512-bit-secret",
+	"your-secret-key", "your_jwt_secret", "shhhhh", "keyboard cat",
+}
+
+// jwtPersonalClaims 包含个人信息的声明及其数据分类(OIDC 标准声明与常见自定义声明)
+var jwtPersonalClaims = map[string]string{
+	"name":               "personal_identifiers",
+	"given_name":         "personal_identifiers",
+	"family_name":        "personal_identifiers",
+	"middle_name":        "personal_identifiers",
+	"nickname":           "personal_identifiers",
+	"preferred_username": "personal_identifiers",
+	"username":           "personal_identifiers",
+	"birthdate":          "personal_identifiers",
+	"gender":             "personal_identifiers",
+	"id_card":            "personal_identifiers",
+	"idcard":             "personal_identifiers",
+	"email":              "contact_info",
+	"phone_number":       "contact_info",
+	"phone":              "contact_info",
+	"mobile":             "contact_info",
+	"address":            "contact_info",
+}
+
+// jwtHashes HMAC 签名算法对应的哈希函数
+var jwtHashes = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// AnalyzeJWT 解码 JWT 的头部与载荷, 输出签名算法、弱签名密钥、过期时间、签发者以及声明中的个人信息
+func AnalyzeJWT(value string, ctx *AnalyzerContext) []Finding {
+	token := strings.Trim(value, " \t\r\n\"'`")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	var header, claims map[string]interface{}
+	if !decodeJWTPart(parts[0], &header) || !decodeJWTPart(parts[1], &claims) {
+		return nil
+	}
+
+	var findings []Finding
+	alg, _ := header["alg"].(string)
+	if strings.EqualFold(alg, "none") {
+		findings = append(findings, Finding{Type: "jwt.algorithm", Value: alg, Severity: SeverityCritical, Description: "token is not signed (alg=none), its claims can be forged"})
+	} else {
+		findings = append(findings, Finding{Type: "jwt.algorithm", Value: alg, Severity: SeverityInfo, Description: "token signing algorithm"})
+	}
+
+	if key, ok := crackJWTKey(parts, alg, ctx.Wordlist); ok {
+		findings = append(findings, Finding{Type: "jwt.weak_key", Value: fmt.Sprintf("%q", key), Severity: SeverityCritical, Category: "credentials", Description: fmt.Sprintf("%s signing key is a weak key from the wordlist, tokens can be forged", alg)})
+	}
+
+	if exp, ok := jwtTime(claims["exp"]); !ok {
+		findings = append(findings, Finding{Type: "jwt.expiry", Value: "none", Severity: SeverityHigh, Description: "token has no exp claim and never expires"})
+	} else if exp.Before(ctx.Now) {
+		findings = append(findings, Finding{Type: "jwt.expiry", Value: exp.UTC().Format(time.RFC3339), Severity: SeverityLow, Description: "token has expired"})
+	} else {
+		findings = append(findings, Finding{Type: "jwt.expiry", Value: exp.UTC().Format(time.RFC3339), Severity: SeverityHigh, Description: "token has not expired yet"})
+	}
+
+	if iss, ok := claims["iss"].(string); ok && iss != "" {
+		findings = append(findings, Finding{Type: "jwt.issuer", Value: iss, Severity: SeverityInfo, Description: "token issuer"})
+	}
+
+	// 按声明名称排序, 保证结果稳定
+	var names []string
+	for name := range claims {
+		if _, ok := jwtPersonalClaims[strings.ToLower(name)]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		claim := jwtClaimString(claims[name])
+		if claim == "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Type:        "jwt.claim",
+			Value:       name + "=" + claim,
+			Severity:    SeverityMedium,
+			Category:    jwtPersonalClaims[strings.ToLower(name)],
+			Description: fmt.Sprintf("personal data in claim %s", name),
+		})
+	}
+	return findings
+}
+
+// decodeJWTPart 解码 base64url 编码的 JSON 对象
+func decodeJWTPart(part string, v interface{}) bool {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v) == nil
+}
+
+// crackJWTKey 使用内置字典与自定义字典验证 HMAC 签名, 返回命中的密钥
+func crackJWTKey(parts []string, alg string, wordlist []string) (string, bool) {
+	newHash, ok := jwtHashes[strings.ToUpper(alg)]
+	if !ok {
+		return "", false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil || len(signature) == 0 {
+		return "", false
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	for _, keys := range [][]string{jwtWeakKeys, wordlist} {
+		for _, key := range keys {
+			mac := hmac.New(newHash, []byte(key))
+			mac.Write(signed)
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// jwtTime 解析 NumericDate 格式(Unix 秒)的时间声明
+func jwtTime(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// jwtClaimString 将声明值转换为字符串, 对象与数组输出为 JSON
+func jwtClaimString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
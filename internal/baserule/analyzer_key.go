@@ -0,0 +1,416 @@
+package baserule
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pemBlockPattern PEM 块, 允许内容被压缩为一行或换行被转义为 \n
+var pemBlockPattern = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]+)-----([\s\S]*?)-----END ([A-Z0-9 ]+)-----`)
+
+// opensshMagic OpenSSH 私钥格式的魔数
+const opensshMagic = "openssh-key-v1\x00"
+
+// PKCS#12 相关的对象标识符
+var (
+	oidPKCS7Data          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7EncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidKeyBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidShroudedKeyBag     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+)
+
+// AnalyzeKey 解析 PEM 编码的私钥、公钥与证书以及 OpenSSH 私钥, 输出类型、密钥长度、是否加密、公钥指纹与证书信息
+func AnalyzeKey(value string, ctx *AnalyzerContext) []Finding {
+	blockType, headers, data, ok := decodePEM(value)
+	if !ok {
+		return nil
+	}
+
+	// 传统 OpenSSL 加密格式在 PEM 头部标记 Proc-Type: 4,ENCRYPTED
+	if strings.Contains(headers["Proc-Type"], "ENCRYPTED") {
+		name := strings.TrimSuffix(blockType, " PRIVATE KEY")
+		return privateKeyFindings(name+" private key", nil, true)
+	}
+
+	switch blockType {
+	case "OPENSSH PRIVATE KEY":
+		return analyzeOpenSSHKey(data)
+	case "ENCRYPTED PRIVATE KEY":
+		return privateKeyFindings("PKCS#8 private key", nil, true)
+	case "CERTIFICATE", "TRUSTED CERTIFICATE", "X509 CERTIFICATE":
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			return certificateFindings(cert, ctx)
+		}
+		return nil
+	case "PUBLIC KEY":
+		if pub, err := x509.ParsePKIXPublicKey(data); err == nil {
+			return publicKeyFindings(pub)
+		}
+		return nil
+	case "RSA PUBLIC KEY":
+		if pub, err := x509.ParsePKCS1PublicKey(data); err == nil {
+			return publicKeyFindings(pub)
+		}
+		return nil
+	}
+	return AnalyzeDER(data, ctx)
+}
+
+// AnalyzeDER 解析 DER 编码的证书、私钥、公钥或 PKCS#12 文件, 无法识别时返回nil
+func AnalyzeDER(data []byte, ctx *AnalyzerContext) []Finding {
+	if cert, err := x509.ParseCertificate(data); err == nil {
+		return certificateFindings(cert, ctx)
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		return privateKeyFindings("", key, false)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return privateKeyFindings("", key, false)
+	}
+	if key, err := x509.ParseECPrivateKey(data); err == nil {
+		return privateKeyFindings("", key, false)
+	}
+	if pub, err := x509.ParsePKIXPublicKey(data); err == nil {
+		return publicKeyFindings(pub)
+	}
+	return analyzePKCS12(data, ctx)
+}
+
+// decodePEM 解析 PEM 块的类型、头部与内容
+// 与 encoding/pem 不同, 允许内容中的换行被替换为空格或转义为 \n(配置文件与 JSON 中常见)
+func decodePEM(value string) (string, map[string]string, []byte, bool) {
+	text := strings.NewReplacer(`\r\n`, "\n", `\n`, "\n", `\r`, "\n").Replace(value)
+	match := pemBlockPattern.FindStringSubmatch(text)
+	if match == nil || match[1] != match[3] {
+		return "", nil, nil, false
+	}
+
+	headers := make(map[string]string)
+	var body strings.Builder
+	for _, line := range strings.Split(match[2], "\n") {
+		if name, headerValue, ok := strings.Cut(line, ":"); ok {
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+			continue
+		}
+		// 去除空白与多次转义残留的反斜杠
+		body.WriteString(strings.ReplaceAll(strings.Join(strings.Fields(line), ""), `\`, ""))
+	}
+
+	data, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil || len(data) == 0 {
+		return "", nil, nil, false
+	}
+	return match[1], headers, data, true
+}
+
+// privateKeyFindings 私钥的分析结果, 加密的私钥无法解析时 key 为nil, 只输出类型与加密状态
+func privateKeyFindings(name string, key interface{}, encrypted bool) []Finding {
+	var pub crypto.PublicKey
+	if signer, ok := key.(crypto.Signer); ok {
+		pub = signer.Public()
+	} else if ecdhKey, ok := key.(*ecdh.PrivateKey); ok {
+		pub = ecdhKey.PublicKey()
+	}
+	algorithm, bits := publicKeyInfo(pub)
+	if name == "" {
+		name = algorithm + " private key"
+	}
+
+	findings := []Finding{
+		{Type: "key.type", Value: name, Severity: SeverityCritical, Category: "credentials", Description: "private key material"},
+	}
+	if bits > 0 {
+		findings = append(findings, Finding{Type: "key.size", Value: strconv.Itoa(bits), Severity: SeverityInfo, Description: "key size in bits"})
+	}
+	if encrypted {
+		findings[0].Severity = SeverityHigh
+		findings = append(findings, Finding{Type: "key.encrypted", Value: "true", Severity: SeverityLow, Description: "private key is protected by a passphrase"})
+	} else {
+		findings = append(findings, Finding{Type: "key.encrypted", Value: "false", Severity: SeverityCritical, Description: "private key is not encrypted and can be used directly"})
+	}
+	if fingerprint := publicKeyFingerprint(pub); fingerprint != "" {
+		findings = append(findings, Finding{Type: "key.fingerprint", Value: fingerprint, Severity: SeverityInfo, Description: "SHA-256 fingerprint of the public key (SubjectPublicKeyInfo)"})
+	}
+	return findings
+}
+
+// publicKeyFindings 公钥的分析结果
+func publicKeyFindings(pub crypto.PublicKey) []Finding {
+	algorithm, bits := publicKeyInfo(pub)
+	findings := []Finding{
+		{Type: "key.type", Value: algorithm + " public key", Severity: SeverityInfo, Description: "public key material"},
+	}
+	if bits > 0 {
+		findings = append(findings, Finding{Type: "key.size", Value: strconv.Itoa(bits), Severity: SeverityInfo, Description: "key size in bits"})
+	}
+	if fingerprint := publicKeyFingerprint(pub); fingerprint != "" {
+		findings = append(findings, Finding{Type: "key.fingerprint", Value: fingerprint, Severity: SeverityInfo, Description: "SHA-256 fingerprint of the public key (SubjectPublicKeyInfo)"})
+	}
+	return findings
+}
+
+// certificateFindings 证书的分析结果: 主题、过期时间、是否自签名以及公钥信息
+func certificateFindings(cert *x509.Certificate, ctx *AnalyzerContext) []Finding {
+	algorithm, bits := publicKeyInfo(cert.PublicKey)
+	findings := []Finding{
+		{Type: "key.type", Value: algorithm + " certificate", Severity: SeverityInfo, Description: "X.509 certificate"},
+	}
+	if bits > 0 {
+		findings = append(findings, Finding{Type: "key.size", Value: strconv.Itoa(bits), Severity: SeverityInfo, Description: "key size in bits"})
+	}
+	findings = append(findings, Finding{Type: "cert.subject", Value: cert.Subject.String(), Severity: SeverityInfo, Description: "certificate subject"})
+
+	expiry := cert.NotAfter.UTC().Format(time.RFC3339)
+	if cert.NotAfter.Before(ctx.Now) {
+		findings = append(findings, Finding{Type: "cert.expiry", Value: expiry, Severity: SeverityLow, Description: "certificate has expired"})
+	} else {
+		findings = append(findings, Finding{Type: "cert.expiry", Value: expiry, Severity: SeverityInfo, Description: "certificate has not expired yet"})
+	}
+
+	selfSigned := bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	findings = append(findings, Finding{Type: "cert.self_signed", Value: strconv.FormatBool(selfSigned), Severity: SeverityInfo, Description: "certificate is signed by its own key"})
+
+	if fingerprint := publicKeyFingerprint(cert.PublicKey); fingerprint != "" {
+		findings = append(findings, Finding{Type: "key.fingerprint", Value: fingerprint, Severity: SeverityInfo, Description: "SHA-256 fingerprint of the public key (SubjectPublicKeyInfo)"})
+	}
+	return findings
+}
+
+// publicKeyInfo 返回公钥算法与密钥长度
+func publicKeyInfo(pub crypto.PublicKey) (string, int) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name, key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	case *ecdh.PublicKey:
+		return "X25519", 256
+	case *dsa.PublicKey:
+		return "DSA", key.P.BitLen()
+	}
+	return "unknown", 0
+}
+
+// publicKeyFingerprint 返回公钥 SubjectPublicKeyInfo 的 SHA-256 指纹(与 ssh-keygen 相同的 SHA256:base64 格式)
+func publicKeyFingerprint(pub crypto.PublicKey) string {
+	if pub == nil {
+		return ""
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	return sha256Fingerprint(der)
+}
+
+// sha256Fingerprint 返回 SHA256:base64 格式的指纹
+func sha256Fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// sshReader 读取 SSH 线路格式(RFC 4251)的数据
+type sshReader struct {
+	data []byte
+	err  bool
+}
+
+// uint32 读取32位无符号整数
+func (r *sshReader) uint32() uint32 {
+	if r.err || len(r.data) < 4 {
+		r.err = true
+		return 0
+	}
+	value := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return value
+}
+
+// bytes 读取带长度前缀的字节串
+func (r *sshReader) bytes() []byte {
+	length := r.uint32()
+	if r.err || uint32(len(r.data)) < length {
+		r.err = true
+		return nil
+	}
+	value := r.data[:length]
+	r.data = r.data[length:]
+	return value
+}
+
+// analyzeOpenSSHKey 解析 OpenSSH 私钥格式的头部: 加密算法与第一个公钥
+// 公钥指纹按公钥的线路格式计算, 与 ssh-keygen -l 的输出一致
+func analyzeOpenSSHKey(data []byte) []Finding {
+	if !bytes.HasPrefix(data, []byte(opensshMagic)) {
+		return nil
+	}
+	r := &sshReader{data: data[len(opensshMagic):]}
+	cipher := string(r.bytes())
+	r.bytes() // kdfname
+	r.bytes() // kdfoptions
+	if keys := r.uint32(); keys < 1 {
+		return nil
+	}
+	pub := r.bytes()
+	if r.err {
+		return nil
+	}
+
+	keyType, bits := sshPublicKeyInfo(pub)
+	findings := []Finding{
+		{Type: "key.type", Value: "OpenSSH private key (" + keyType + ")", Severity: SeverityCritical, Category: "credentials", Description: "private key material"},
+	}
+	if bits > 0 {
+		findings = append(findings, Finding{Type: "key.size", Value: strconv.Itoa(bits), Severity: SeverityInfo, Description: "key size in bits"})
+	}
+	if cipher != "none" {
+		findings[0].Severity = SeverityHigh
+		findings = append(findings, Finding{Type: "key.encrypted", Value: "true", Severity: SeverityLow, Description: "private key is protected by a passphrase (" + cipher + ")"})
+	} else {
+		findings = append(findings, Finding{Type: "key.encrypted", Value: "false", Severity: SeverityCritical, Description: "private key is not encrypted and can be used directly"})
+	}
+	findings = append(findings, Finding{Type: "key.fingerprint", Value: sha256Fingerprint(pub), Severity: SeverityInfo, Description: "SHA-256 fingerprint of the SSH public key"})
+	return findings
+}
+
+// sshPublicKeyInfo 返回 SSH 公钥的类型与密钥长度
+func sshPublicKeyInfo(blob []byte) (string, int) {
+	r := &sshReader{data: blob}
+	keyType := string(r.bytes())
+	bits := 0
+	switch keyType {
+	case "ssh-rsa":
+		r.bytes() // e
+		bits = new(big.Int).SetBytes(r.bytes()).BitLen()
+	case "ssh-dss":
+		bits = new(big.Int).SetBytes(r.bytes()).BitLen()
+	case "ssh-ed25519", "sk-ssh-ed25519@openssh.com":
+		bits = 256
+	case "ecdsa-sha2-nistp256", "sk-ecdsa-sha2-nistp256@openssh.com":
+		bits = 256
+	case "ecdsa-sha2-nistp384":
+		bits = 384
+	case "ecdsa-sha2-nistp521":
+		bits = 521
+	}
+	if r.err {
+		return keyType, 0
+	}
+	return keyType, bits
+}
+
+// pkcs12PFX PKCS#12 文件的外层结构(RFC 7292)
+type pkcs12PFX struct {
+	Version  int
+	AuthSafe pkcs12ContentInfo
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs12ContentInfo PKCS#7 ContentInfo
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+// pkcs12SafeBag PKCS#12 SafeBag
+type pkcs12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue `asn1:"tag:0,explicit"`
+	Attributes asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs12CertBag PKCS#12 CertBag
+type pkcs12CertBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// analyzePKCS12 解析 PKCS#12 文件的结构, 输出未加密的私钥与证书的分析结果
+// 加密的内容需要口令才能解析, 只输出加密状态
+func analyzePKCS12(data []byte, ctx *AnalyzerContext) []Finding {
+	var pfx pkcs12PFX
+	if rest, err := asn1.Unmarshal(data, &pfx); err != nil || len(rest) != 0 || pfx.Version != 3 || !pfx.AuthSafe.ContentType.Equal(oidPKCS7Data) {
+		return nil
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil
+	}
+	var contents []pkcs12ContentInfo
+	if _, err := asn1.Unmarshal(authSafe, &contents); err != nil {
+		return nil
+	}
+
+	encrypted := 0
+	var keys []interface{}
+	var certs []*x509.Certificate
+	for _, content := range contents {
+		if content.ContentType.Equal(oidPKCS7EncryptedData) {
+			encrypted++
+			continue
+		}
+		if !content.ContentType.Equal(oidPKCS7Data) {
+			continue
+		}
+		var bagsData []byte
+		if _, err := asn1.Unmarshal(content.Content.Bytes, &bagsData); err != nil {
+			continue
+		}
+		var bags []pkcs12SafeBag
+		if _, err := asn1.Unmarshal(bagsData, &bags); err != nil {
+			continue
+		}
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidKeyBag):
+				if key, err := x509.ParsePKCS8PrivateKey(bag.Value.Bytes); err == nil {
+					keys = append(keys, key)
+				}
+			case bag.ID.Equal(oidShroudedKeyBag):
+				encrypted++
+			case bag.ID.Equal(oidCertBag):
+				var certBag pkcs12CertBag
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &certBag); err != nil {
+					continue
+				}
+				if cert, err := x509.ParseCertificate(certBag.Data); err == nil {
+					certs = append(certs, cert)
+				}
+			}
+		}
+	}
+
+	findings := []Finding{
+		{Type: "key.type", Value: "PKCS#12 keystore", Severity: SeverityHigh, Category: "credentials", Description: "PKCS#12 keystore containing keys or certificates"},
+	}
+	if len(keys) > 0 {
+		findings[0].Severity = SeverityCritical
+	} else if encrypted > 0 {
+		findings = append(findings, Finding{Type: "key.encrypted", Value: "true", Severity: SeverityLow, Description: "keystore content is protected by a password"})
+	}
+	for _, key := range keys {
+		findings = append(findings, privateKeyFindings("", key, false)...)
+	}
+	for _, cert := range certs {
+		findings = append(findings, certificateFindings(cert, ctx)...)
+	}
+	return findings
+}
@@ -0,0 +1,75 @@
+package baserule
+
+import (
+	"math"
+	"strings"
+)
+
+// 熵计算支持的字符集
+const (
+	EntropyCharsetBase64 = "base64"
+	EntropyCharsetHex    = "hex"
+)
+
+// EntropyMinTokenLength 按字符集切分时参与熵计算的最短片段长度
+const EntropyMinTokenLength = 16
+
+const (
+	base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=-_"
+	hexChars    = "0123456789abcdefABCDEF"
+)
+
+// ShannonEntropy 计算字符串的香农熵(单位: bit/字符)
+func ShannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, c := range s {
+		counts[c]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		freq := float64(count) / float64(total)
+		entropy -= freq * math.Log2(freq)
+	}
+	return entropy
+}
+
+// CharsetEntropy 计算字符串在指定字符集下的最大熵值
+// 字符串按字符集切分为连续片段, 仅长度不小于 EntropyMinTokenLength 的片段参与计算
+// 字符集为空时直接计算整个字符串的熵
+func CharsetEntropy(s, charset string) float64 {
+	var chars string
+	switch strings.ToLower(charset) {
+	case EntropyCharsetBase64:
+		chars = base64Chars
+	case EntropyCharsetHex:
+		chars = hexChars
+	default:
+		return ShannonEntropy(s)
+	}
+
+	maxEntropy := 0.0
+	for _, token := range strings.FieldsFunc(s, func(c rune) bool { return !strings.ContainsRune(chars, c) }) {
+		if len(token) < EntropyMinTokenLength {
+			continue
+		}
+		maxEntropy = math.Max(maxEntropy, ShannonEntropy(token))
+	}
+	return maxEntropy
+}
+
+// CheckEntropy 判断提取值是否满足规则的熵阈值, 返回计算出的熵值
+// 未配置熵阈值的规则始终通过检查
+func (r *Rule) CheckEntropy(value string) (float64, bool) {
+	if r.Entropy <= 0 {
+		return 0, true
+	}
+	entropy := CharsetEntropy(value, r.EntropyCharset)
+	return entropy, entropy > r.Entropy
+}
@@ -0,0 +1,72 @@
+package baserule
+
+import (
+	"math"
+	"testing"
+)
+
+// TestShannonEntropy 测试香农熵计算
+func TestShannonEntropy(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected float64
+	}{
+		{"", 0},
+		{"aaaaaaaa", 0},
+		{"abababab", 1},
+		{"abcdabcd", 2},
+	}
+
+	for _, tc := range testCases {
+		if got := ShannonEntropy(tc.value); math.Abs(got-tc.expected) > 1e-9 {
+			t.Errorf("ShannonEntropy(%q) = %f, expected %f", tc.value, got, tc.expected)
+		}
+	}
+}
+
+// TestCharsetEntropy 测试按字符集切分后的熵计算
+func TestCharsetEntropy(t *testing.T) {
+	// 短片段不参与计算
+	if got := CharsetEntropy("key=abc123", EntropyCharsetBase64); got != 0 {
+		t.Errorf("expected 0 for short token, got %f", got)
+	}
+
+	// 取最大熵值的片段
+	value := "aaaaaaaaaaaaaaaaaaaa Zx8Q2mP7vL4kR9tW1yB6nC3dF5gH"
+	if got := CharsetEntropy(value, EntropyCharsetBase64); got < 4.5 {
+		t.Errorf("expected high entropy for random token, got %f", got)
+	}
+
+	// hex 字符集会切断非十六进制字符
+	if got := CharsetEntropy("Zx8Q2mP7vL4kR9tW1yB6nC3dF5gH", EntropyCharsetHex); got != 0 {
+		t.Errorf("expected 0 for non-hex token, got %f", got)
+	}
+}
+
+// TestRuleMatchSampleWithEntropy 测试熵检测规则的样本校验
+func TestRuleMatchSampleWithEntropy(t *testing.T) {
+	rule := Rule{
+		Name:           "High Entropy Secret",
+		FRegex:         `(?i)secret\s*=\s*"([a-z0-9]+)"`,
+		SecretGroup:    1,
+		Entropy:        4.3,
+		EntropyCharset: EntropyCharsetBase64,
+	}
+
+	for _, engine := range []RegexEngine{RegexEngineGo, RegexEngineJava} {
+		matcher, err := NewRegexMatcher(rule.FRegex, engine)
+		if err != nil {
+			t.Fatalf("NewRegexMatcher failed: %v", err)
+		}
+
+		rule.SampleCode = `secret = "aaaaaaaaaaaaaaaaaaaa"`
+		if ok, _ := rule.MatchSample(matcher); ok {
+			t.Errorf("[%s] expected low entropy sample not to match", engine)
+		}
+
+		rule.SampleCode = `secret = "aaaaaaaaaaaaaaaaaaaa" secret = "Zx8Q2mP7vL4kR9tW1yB6nC3dF5gH"`
+		if ok, err := rule.MatchSample(matcher); !ok || err != nil {
+			t.Errorf("[%s] expected high entropy sample to match, err: %v", engine, err)
+		}
+	}
+}
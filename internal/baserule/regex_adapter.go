@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dlclark/regexp2"
 )
@@ -26,6 +27,7 @@ type RegexMatcher interface {
 // MatchResult 定义匹配结果接口
 type MatchResult interface {
 	String() string
+	Index() int // 匹配在原文中的字节位置
 	Groups() []Group
 	FindNextMatch() (MatchResult, error)
 }
@@ -33,6 +35,7 @@ type MatchResult interface {
 // Group 定义分组接口
 type Group interface {
 	String() string
+	Index() int // 分组在原文中的字节位置, 未参与匹配时为 -1
 	Captures() []Capture
 }
 
@@ -61,17 +64,13 @@ func (m *GoRegexMatcher) MatchString(s string) (bool, error) {
 }
 
 // FindStringMatch 查找第一个匹配
+// 一次性计算全部匹配位置, 使后续匹配保留完整上下文(^、\b 等)并正确跳过空匹配
 func (m *GoRegexMatcher) FindStringMatch(s string) (MatchResult, error) {
-	index := m.regex.FindStringIndex(s)
-	if index == nil {
+	indexes := m.regex.FindAllStringSubmatchIndex(s, -1)
+	if len(indexes) == 0 {
 		return nil, nil
 	}
-	return &GoMatchResult{
-		s:     s,
-		start: index[0],
-		end:   index[1],
-		regex: m.regex,
-	}, nil
+	return newGoMatchResult(s, indexes, 0), nil
 }
 
 // FindAllString 查找所有匹配
@@ -81,10 +80,24 @@ func (m *GoRegexMatcher) FindAllString(s string, n int) []string {
 
 // GoMatchResult 实现 Go 标准库的匹配结果
 type GoMatchResult struct {
-	s     string
-	start int
-	end   int
-	regex *regexp.Regexp
+	s       string
+	start   int
+	end     int
+	groups  []int   // 各分组在原文中的起止位置, 未参与匹配的分组为 -1
+	indexes [][]int // 全部匹配的分组位置
+	next    int     // 下一个匹配在 indexes 中的序号
+}
+
+// newGoMatchResult 创建第 i 个匹配的结果
+func newGoMatchResult(s string, indexes [][]int, i int) *GoMatchResult {
+	return &GoMatchResult{
+		s:       s,
+		start:   indexes[i][0],
+		end:     indexes[i][1],
+		groups:  indexes[i],
+		indexes: indexes,
+		next:    i + 1,
+	}
 }
 
 // String 返回匹配的字符串
@@ -95,31 +108,39 @@ func (r *GoMatchResult) String() string {
 	return r.s[r.start:r.end]
 }
 
+// Index 返回匹配在原文中的字节位置
+func (r *GoMatchResult) Index() int {
+	return r.start
+}
+
 // Groups 返回分组信息
 func (r *GoMatchResult) Groups() []Group {
-	return []Group{&GoGroup{value: r.String()}}
+	if len(r.groups) == 0 {
+		return []Group{&GoGroup{value: r.String(), index: r.start}}
+	}
+	groups := make([]Group, 0, len(r.groups)/2)
+	for i := 0; i+1 < len(r.groups); i += 2 {
+		value, index := "", -1
+		if r.groups[i] >= 0 && r.groups[i+1] >= 0 {
+			value, index = r.s[r.groups[i]:r.groups[i+1]], r.groups[i]
+		}
+		groups = append(groups, &GoGroup{value: value, index: index})
+	}
+	return groups
 }
 
 // FindNextMatch 查找下一个匹配
 func (r *GoMatchResult) FindNextMatch() (MatchResult, error) {
-	if r.end >= len(r.s) {
-		return nil, nil
-	}
-	index := r.regex.FindStringIndex(r.s[r.end:])
-	if index == nil {
+	if r.next >= len(r.indexes) {
 		return nil, nil
 	}
-	return &GoMatchResult{
-		s:     r.s,
-		start: r.end + index[0],
-		end:   r.end + index[1],
-		regex: r.regex,
-	}, nil
+	return newGoMatchResult(r.s, r.indexes, r.next), nil
 }
 
 // GoGroup 实现 Go 标准库的分组
 type GoGroup struct {
 	value string
+	index int
 }
 
 // String 返回分组值
@@ -127,6 +148,11 @@ func (g *GoGroup) String() string {
 	return g.value
 }
 
+// Index 返回分组在原文中的字节位置
+func (g *GoGroup) Index() int {
+	return g.index
+}
+
 // Captures 返回捕获信息
 func (g *GoGroup) Captures() []Capture {
 	return []Capture{&GoCapture{value: g.value}}
@@ -158,6 +184,11 @@ func NewJavaRegexMatcher(pattern string) (*JavaRegexMatcher, error) {
 	return &JavaRegexMatcher{regex: regex}, nil
 }
 
+// SetMatchTimeout 设置单次匹配的超时时间
+func (m *JavaRegexMatcher) SetMatchTimeout(timeout time.Duration) {
+	m.regex.MatchTimeout = timeout
+}
+
 // MatchString 匹配字符串
 func (m *JavaRegexMatcher) MatchString(s string) (bool, error) {
 	return m.regex.MatchString(s)
@@ -172,7 +203,7 @@ func (m *JavaRegexMatcher) FindStringMatch(s string) (MatchResult, error) {
 	if match == nil {
 		return nil, nil
 	}
-	return &JavaMatchResult{match: match, regex: m.regex}, nil
+	return &JavaMatchResult{match: match, regex: m.regex, offsets: newRuneOffsets(s)}, nil
 }
 
 // FindAllString 查找所有匹配
@@ -194,8 +225,9 @@ func (m *JavaRegexMatcher) FindAllString(s string, n int) []string {
 
 // JavaMatchResult 实现 regexp2 的匹配结果
 type JavaMatchResult struct {
-	match *regexp2.Match
-	regex *regexp2.Regexp
+	match   *regexp2.Match
+	regex   *regexp2.Regexp
+	offsets *runeOffsets // regexp2 的位置以字符计算, 转换为字节位置
 }
 
 // String 返回匹配的字符串
@@ -206,6 +238,14 @@ func (r *JavaMatchResult) String() string {
 	return r.match.String()
 }
 
+// Index 返回匹配在原文中的字节位置
+func (r *JavaMatchResult) Index() int {
+	if r.match == nil {
+		return -1
+	}
+	return r.offsets.byteOffset(r.match.Index)
+}
+
 // Groups 返回分组信息
 func (r *JavaMatchResult) Groups() []Group {
 	if r.match == nil {
@@ -215,7 +255,7 @@ func (r *JavaMatchResult) Groups() []Group {
 	for i := 0; i < r.match.GroupCount(); i++ {
 		group := r.match.GroupByNumber(i)
 		if group != nil {
-			groups = append(groups, &JavaGroup{group: group})
+			groups = append(groups, &JavaGroup{group: group, offsets: r.offsets})
 		}
 	}
 	return groups
@@ -230,12 +270,13 @@ func (r *JavaMatchResult) FindNextMatch() (MatchResult, error) {
 	if err != nil || match == nil {
 		return nil, err
 	}
-	return &JavaMatchResult{match: match, regex: r.regex}, nil
+	return &JavaMatchResult{match: match, regex: r.regex, offsets: r.offsets}, nil
 }
 
 // JavaGroup 实现 regexp2 的分组
 type JavaGroup struct {
-	group *regexp2.Group
+	group   *regexp2.Group
+	offsets *runeOffsets
 }
 
 // String 返回分组值
@@ -246,6 +287,14 @@ func (g *JavaGroup) String() string {
 	return g.group.String()
 }
 
+// Index 返回分组在原文中的字节位置, 未参与匹配时为 -1
+func (g *JavaGroup) Index() int {
+	if g.group == nil || len(g.group.Captures) == 0 {
+		return -1
+	}
+	return g.offsets.byteOffset(g.group.Index)
+}
+
 // Captures 返回捕获信息
 func (g *JavaGroup) Captures() []Capture {
 	if g.group == nil {
@@ -268,6 +317,36 @@ func (c *JavaCapture) String() string {
 	return c.capture.String()
 }
 
+// runeOffsets 将 regexp2 的字符位置转换为字节位置
+// 记录上次转换的位置, 匹配位置递增时总体为线性复杂度; 纯 ASCII 内容直接返回
+type runeOffsets struct {
+	s         string
+	ascii     bool
+	runeIndex int
+	byteIndex int
+}
+
+// newRuneOffsets 创建位置转换器
+func newRuneOffsets(s string) *runeOffsets {
+	return &runeOffsets{s: s, ascii: utf8.RuneCountInString(s) == len(s)}
+}
+
+// byteOffset 返回第 index 个字符的字节位置(无效 UTF-8 字节与 regexp2 一样按单个字符计算)
+func (o *runeOffsets) byteOffset(index int) int {
+	if o.ascii {
+		return index
+	}
+	if index < o.runeIndex {
+		o.runeIndex, o.byteIndex = 0, 0
+	}
+	for o.runeIndex < index && o.byteIndex < len(o.s) {
+		_, size := utf8.DecodeRuneInString(o.s[o.byteIndex:])
+		o.byteIndex += size
+		o.runeIndex++
+	}
+	return o.byteIndex
+}
+
 // NewRegexMatcher 根据引擎类型创建正则匹配器
 func NewRegexMatcher(pattern string, engine RegexEngine) (RegexMatcher, error) {
 	switch engine {
@@ -297,3 +376,15 @@ func TryCompileWithFallback(pattern string) (RegexMatcher, error) {
 	// 两种引擎都失败
 	return nil, fmt.Errorf("both engines failed to compile regex: %v", err)
 }
+
+// MatcherEngine 返回匹配器实际使用的引擎类型
+func MatcherEngine(matcher RegexMatcher) RegexEngine {
+	switch matcher.(type) {
+	case *GoRegexMatcher:
+		return RegexEngineGo
+	case *JavaRegexMatcher:
+		return RegexEngineJava
+	default:
+		return ""
+	}
+}
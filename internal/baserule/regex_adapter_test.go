@@ -1,6 +1,7 @@
 package baserule
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -156,3 +157,60 @@ func TestNewRegexMatcher(t *testing.T) {
 		t.Errorf("Expected match, but it didn't")
 	}
 }
+
+// TestGoMatchResultFindNextMatch 测试 Go 引擎遍历匹配时保留上下文并跳过空匹配
+func TestGoMatchResultFindNextMatch(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		content  string
+		expected []string
+	}{
+		{`^a`, "aa", []string{"a"}},
+		{`\bab`, "abab ab", []string{"ab", "ab"}},
+		{`x*`, "axxb", []string{"", "xx", ""}},
+		{`\d+`, "1 22 333", []string{"1", "22", "333"}},
+	}
+
+	for _, tc := range testCases {
+		matcher, err := NewGoRegexMatcher(tc.pattern)
+		if err != nil {
+			t.Fatalf("NewGoRegexMatcher(%q) failed: %v", tc.pattern, err)
+		}
+		var actual []string
+		match, _ := matcher.FindStringMatch(tc.content)
+		for match != nil && len(actual) <= len(tc.expected) {
+			actual = append(actual, match.String())
+			match, _ = match.FindNextMatch()
+		}
+		if strings.Join(actual, ",") != strings.Join(tc.expected, ",") || len(actual) != len(tc.expected) {
+			t.Errorf("matches of %q in %q = %q, expected %q", tc.pattern, tc.content, actual, tc.expected)
+		}
+	}
+}
+
+// TestExtractSpanIndex 测试两种引擎返回的提取值字节位置, 包括多字节字符与重复出现的值
+func TestExtractSpanIndex(t *testing.T) {
+	content := "密码 token=abc123 和 token=abc123"
+	rule := Rule{FRegex: `token=([a-z0-9]+)`, SecretGroup: 1}
+	expected := []int{strings.Index(content, "abc123"), strings.LastIndex(content, "abc123")}
+
+	for _, engine := range []RegexEngine{RegexEngineGo, RegexEngineJava} {
+		matcher, err := NewRegexMatcher(rule.ScanPattern(), engine)
+		if err != nil {
+			t.Fatalf("NewRegexMatcher(%s) failed: %v", engine, err)
+		}
+		var indexes []int
+		match, err := matcher.FindStringMatch(content)
+		for match != nil && err == nil {
+			value, index := rule.ExtractSpan(match)
+			if content[index:index+len(value)] != value {
+				t.Errorf("%s: index %d does not point to %q", engine, index, value)
+			}
+			indexes = append(indexes, index)
+			match, err = match.FindNextMatch()
+		}
+		if len(indexes) != 2 || indexes[0] != expected[0] || indexes[1] != expected[1] {
+			t.Errorf("%s: indexes = %v, expected %v", engine, indexes, expected)
+		}
+	}
+}
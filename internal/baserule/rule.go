@@ -2,22 +2,52 @@ package baserule
 
 // Rule 表示单个检测规则
 type Rule struct {
+	ID     string `yaml:"id" json:"id"`           // 稳定的规则ID(可选, 全局唯一)
 	Name   string `yaml:"name" json:"name"`       // 规则名称
 	FRegex string `yaml:"f_regex" json:"f_regex"` // 正则表达式
 	Loaded bool   `yaml:"loaded" json:"loaded"`   // 是否启用规则
 
 	Engine       string `yaml:"engine" json:"engine"`               // 规则匹配引擎(支持go和java，当使用hae规则时，nfa和dfa会自动转为java)
 	Sensitive    bool   `yaml:"sensitive" json:"sensitive"`         // 是否敏感信息
+	Severity     string `yaml:"severity" json:"severity"`           // 严重等级(info/low/medium/high/critical, 为空时由sensitive映射)
+	Confidence   string `yaml:"confidence" json:"confidence"`       // 置信度(low/medium/high, 默认medium)
+	Priority     int    `yaml:"priority" json:"priority"`           // 重叠结果处理优先级(越大越优先, 默认0)
 	ContextLeft  int    `yaml:"context_left" json:"context_left"`   // 匹配结果向左扩充字符数
 	ContextRight int    `yaml:"context_right" json:"context_right"` // 匹配结果向右扩充字符数
 	SampleCode   string `yaml:"sample_code" json:"sample_code"`     // 用于测试正则的示例代码
 
+	Samples         []RuleSample `yaml:"samples" json:"samples"`                   // 正样例及期望的提取值(规则测试模式)
+	NegativeSamples []string     `yaml:"negative_samples" json:"negative_samples"` // 不应产生结果的负样例(规则测试模式)
+
+	Category string   `yaml:"category" json:"category"` // 数据分类(如 personal_identifiers/contact_info/credentials/infrastructure)
+	Tags     []string `yaml:"tags" json:"tags"`         // 自定义标签(如 pipl/gdpr/pci-dss)
+
+	Description string   `yaml:"description" json:"description"` // 规则描述
+	Remediation string   `yaml:"remediation" json:"remediation"` // 修复建议
+	References  []string `yaml:"references" json:"references"`   // 参考链接
+
+	SecretGroup    int     `yaml:"secret_group" json:"secret_group"`       // 提取值所在的捕获组(0表示整个匹配)
+	Entropy        float64 `yaml:"entropy" json:"entropy"`                 // 香农熵阈值(大于0时仅保留熵值超过阈值的结果)
+	EntropyCharset string  `yaml:"entropy_charset" json:"entropy_charset"` // 熵计算字符集(base64/hex, 为空时按整个提取值计算)
+	Validator      string  `yaml:"validator" json:"validator"`             // 提取值校验器(idcard/luhn/uscc/iban/ipv4)
+	Analyzer       string  `yaml:"analyzer" json:"analyzer"`               // 提取值分析器(jwt/key), 分析结果作为独立的结果输出
+
+	Keywords []string `yaml:"keywords" json:"keywords"` // 预过滤关键字(不区分大小写), 内容包含任一关键字时才执行正则; 为空时从正则中自动提取
+
+	Allowlist Allowlist `yaml:"allowlist" json:"allowlist"` // 白名单, 命中的结果会被忽略
+
 	SRegex string `yaml:"s_regex" json:"s_regex"` // 二次匹配规则(未实现)
 	Format string `yaml:"format" json:"format"`   // 结果提取格式(未实现)
 	Color  string `yaml:"color" json:"color"`     // 结果颜色显示(未实现)
 	Scope  string `yaml:"scope" json:"scope"`     // 规则匹配范围(未实现)
 }
 
+// RuleSample 规则正样例
+type RuleSample struct {
+	Text   string   `yaml:"text" json:"text"`     // 样例内容
+	Expect []string `yaml:"expect" json:"expect"` // 期望的提取值(不区分顺序), 为空时仅要求产生结果
+}
+
 // Rules 表示规则组
 type Rules struct {
 	Group string `yaml:"group" json:"group"` // 规则组名称
@@ -26,5 +56,7 @@ type Rules struct {
 
 // RuleConfig 表示完整的规则配置
 type RuleConfig struct {
-	Rules []Rules `yaml:"rules" json:"rules"`
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"` // 引用的其他规则文件或目录(相对于当前文件)
+	Rules   []Rules  `yaml:"rules" json:"rules"`
+	Files   []string `yaml:"-" json:"-"` // 按加载顺序排列的规则文件(引用方在被引用的文件之前)
 }
@@ -0,0 +1,40 @@
+package baserule
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateRuleIDs 测试规则ID的格式与唯一性校验
+func TestValidateRuleIDs(t *testing.T) {
+	config := &RuleConfig{
+		Rules: []Rules{
+			{
+				Group: "group-a",
+				Rule: []Rule{
+					{ID: "email", Name: "Email"},
+					{ID: "cloud-key", Name: "Cloud Key"},
+					{Name: "No ID"},
+				},
+			},
+			{
+				Group: "group-b",
+				Rule: []Rule{
+					{ID: "email", Name: "Email Copy", Loaded: false},
+					{ID: "bad id", Name: "Bad ID"},
+				},
+			},
+		},
+	}
+
+	invalid := config.validateRuleIDs()
+	if len(invalid) != 2 {
+		t.Fatalf("expected 2 invalid rules, got %d: %v", len(invalid), invalid)
+	}
+	if !strings.Contains(invalid[0], "Email Copy") || !strings.Contains(invalid[0], "group-a") {
+		t.Errorf("expected duplicate id error to reference both rules, got %s", invalid[0])
+	}
+	if !strings.Contains(invalid[1], "bad id") {
+		t.Errorf("expected format error for 'bad id', got %s", invalid[1])
+	}
+}
@@ -0,0 +1,69 @@
+package baserule
+
+// RuleInfo 规则摘要, 用于输出生效规则清单
+type RuleInfo struct {
+	Group      string   `json:"group"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Engine     string   `json:"engine"` // 扫描时实际使用的引擎(go/java), 编译失败时为 invalid
+	Severity   string   `json:"severity"`
+	Confidence string   `json:"confidence"`
+	Category   string   `json:"category"`
+	Tags       []string `json:"tags"`
+	Sensitive  bool     `json:"sensitive"`
+	HasSample  bool     `json:"has_sample"`
+}
+
+// NewRuleInfo 生成规则摘要
+func NewRuleInfo(group string, rule Rule) RuleInfo {
+	engine := "invalid"
+	if matcher, err := rule.CompileMatcher(); err == nil {
+		engine = string(MatcherEngine(matcher))
+	}
+
+	return RuleInfo{
+		Group:      group,
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Engine:     engine,
+		Severity:   rule.GetSeverity(),
+		Confidence: rule.GetConfidence(),
+		Category:   rule.Category,
+		Tags:       rule.Tags,
+		Sensitive:  rule.Sensitive,
+		HasSample:  rule.SampleCode != "",
+	}
+}
+
+// DescribeRules 按规则文件中的组顺序生成筛选后规则的摘要
+func (c *RuleConfig) DescribeRules(rules RuleMap) []RuleInfo {
+	var infos []RuleInfo
+	seen := make(map[string]bool)
+	for _, group := range c.Rules {
+		if seen[group.Group] {
+			continue
+		}
+		seen[group.Group] = true
+		for _, rule := range rules[group.Group] {
+			infos = append(infos, NewRuleInfo(group.Group, rule))
+		}
+	}
+	return infos
+}
+
+// FindRules 按规则名称或ID查找规则(包含未启用的规则), 返回包含命中规则的规则组
+func (c *RuleConfig) FindRules(nameOrID string) []Rules {
+	var found []Rules
+	for _, group := range c.Rules {
+		matched := Rules{Group: group.Group}
+		for _, rule := range group.Rule {
+			if rule.Name == nameOrID || (rule.ID != "" && rule.ID == nameOrID) {
+				matched.Rule = append(matched.Rule, rule)
+			}
+		}
+		if len(matched.Rule) > 0 {
+			found = append(found, matched)
+		}
+	}
+	return found
+}
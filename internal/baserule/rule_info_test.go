@@ -0,0 +1,63 @@
+package baserule
+
+import "testing"
+
+// TestCompileMatcherEngine 测试扫描时实际使用的引擎
+func TestCompileMatcherEngine(t *testing.T) {
+	testCases := []struct {
+		engine   string
+		regex    string
+		expected RegexEngine
+	}{
+		{"", `\d{6}`, RegexEngineGo},
+		{"", `\w+(?!\.js)`, RegexEngineJava},
+		{"nfa", `\d{6}`, RegexEngineJava},
+		{"dfa", `\d{6}`, RegexEngineJava},
+		{"go", `\d{6}`, RegexEngineGo},
+	}
+
+	for _, tc := range testCases {
+		rule := Rule{Name: "test", FRegex: tc.regex, Engine: tc.engine}
+		matcher, err := rule.CompileMatcher()
+		if err != nil {
+			t.Fatalf("CompileMatcher(%q, %q) failed: %v", tc.engine, tc.regex, err)
+		}
+		if engine := MatcherEngine(matcher); engine != tc.expected {
+			t.Errorf("CompileMatcher(%q, %q) engine = %s, expected %s", tc.engine, tc.regex, engine, tc.expected)
+		}
+	}
+
+	if _, err := (&Rule{FRegex: `(?!x)`, Engine: "go"}).CompileMatcher(); err == nil {
+		t.Errorf("expected error when go engine is forced for unsupported syntax")
+	}
+}
+
+// TestDescribeRules 测试规则摘要保持规则文件顺序
+func TestDescribeRules(t *testing.T) {
+	config := &RuleConfig{Rules: []Rules{
+		{Group: "B", Rule: []Rule{{ID: "b1", Name: "B1", FRegex: `b`, Loaded: true, SampleCode: "b"}}},
+		{Group: "A", Rule: []Rule{
+			{ID: "a1", Name: "A1", FRegex: `a`, Loaded: true, Sensitive: true},
+			{ID: "a2", Name: "A2", FRegex: `a`, Loaded: false},
+		}},
+	}}
+
+	infos := config.DescribeRules(config.FilterRules(FilterOptions{}))
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(infos))
+	}
+	if infos[0].ID != "b1" || !infos[0].HasSample || infos[0].Engine != "go" || infos[0].Severity != "info" {
+		t.Errorf("unexpected first rule info: %+v", infos[0])
+	}
+	if infos[1].ID != "a1" || infos[1].HasSample || infos[1].Severity != "high" {
+		t.Errorf("unexpected second rule info: %+v", infos[1])
+	}
+
+	found := config.FindRules("a2")
+	if len(found) != 1 || found[0].Group != "A" || found[0].Rule[0].Name != "A2" {
+		t.Errorf("expected disabled rule to be found by id, got %+v", found)
+	}
+	if found := config.FindRules("B1"); len(found) != 1 {
+		t.Errorf("expected rule to be found by name, got %+v", found)
+	}
+}
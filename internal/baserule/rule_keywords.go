@@ -0,0 +1,269 @@
+package baserule
+
+import (
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+const (
+	minKeywordLength = 3  // 自动提取的关键字最小长度, 过短的关键字几乎命中所有内容
+	maxKeywordCount  = 32 // 自动提取的关键字最大数量, 过多的分支不再预过滤
+)
+
+// FoldKeyword 将关键字或内容转换为预过滤比较使用的小写形式
+// 与 Go 正则 (?i) 的大小写折叠保持一致: ſ(U+017F) 折叠为 s, K(U+212A) 通过 ToLower 折叠为 k
+func FoldKeyword(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == 'ſ' {
+			return 's'
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// PrefilterKeywords 返回规则的预过滤关键字(已折叠为小写), engine 为规则编译后实际使用的正则引擎
+// 配置了 keywords 时使用配置的关键字; 否则对 Go 引擎规则从正则中提取每个匹配都必须包含的字面量, 无法提取时返回nil(不预过滤)
+// 字面量按 Go 正则语法提取, Java 引擎(regexp2)对部分语法的解释不同, 提取的字面量不一定必需, 因此不自动提取
+func (r *Rule) PrefilterKeywords(engine RegexEngine) []string {
+	if len(r.Keywords) > 0 {
+		return normalizePrefilterKeywords(r.Keywords)
+	}
+	if engine != RegexEngineGo {
+		return nil
+	}
+	return DeriveKeywords(r.ScanPattern())
+}
+
+// normalizePrefilterKeywords 折叠并去重关键字, 忽略空关键字
+func normalizePrefilterKeywords(keywords []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		folded := FoldKeyword(strings.TrimSpace(keyword))
+		if folded != "" && !seen[folded] {
+			seen[folded] = true
+			result = append(result, folded)
+		}
+	}
+	return result
+}
+
+// DeriveKeywords 从正则中提取必需字面量: 任意匹配都至少包含其中一个字面量
+// 仅支持 Go 正则语法可以解析的表达式, 无法保证时返回nil
+func DeriveKeywords(pattern string) []string {
+	regex, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	keywords, ok := requiredLiterals(regex.Simplify())
+	if !ok || len(keywords) == 0 || len(keywords) > maxKeywordCount {
+		return nil
+	}
+	for _, keyword := range keywords {
+		if len([]rune(keyword)) < minKeywordLength {
+			return nil
+		}
+	}
+	return normalizePrefilterKeywords(keywords)
+}
+
+// requiredLiterals 返回任意匹配都至少包含其中一个的字面量集合, 无法确定时返回false
+func requiredLiterals(regex *syntax.Regexp) ([]string, bool) {
+	switch regex.Op {
+	case syntax.OpLiteral:
+		return []string{string(regex.Rune)}, true
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(regex.Sub[0])
+	case syntax.OpRepeat:
+		if regex.Min < 1 {
+			return nil, false
+		}
+		return requiredLiterals(regex.Sub[0])
+	case syntax.OpConcat:
+		return concatLiterals(regex.Sub)
+	case syntax.OpAlternate:
+		var literals []string
+		for _, sub := range regex.Sub {
+			subLiterals, ok := requiredLiterals(sub)
+			if !ok {
+				return nil, false
+			}
+			literals = append(literals, subLiterals...)
+		}
+		return literals, true
+	default:
+		return nil, false
+	}
+}
+
+// concatLiterals 从连接表达式的子表达式中选择最有区分度的必需字面量集合
+// 相邻的确定字面量(字面量、分组与分支)展开为组合后的完整字面量, 以集合中最短字面量的长度作为区分度
+func concatLiterals(subs []*syntax.Regexp) ([]string, bool) {
+	var best []string
+	bestScore := 0
+	consider := func(literals []string) {
+		if score := shortestLength(literals); score > bestScore {
+			best, bestScore = literals, score
+		}
+	}
+
+	var run []string
+	for _, sub := range subs {
+		if literals, ok := exactLiterals(sub); ok {
+			if run == nil {
+				run = literals
+				continue
+			}
+			if product, ok := productLiterals(run, literals); ok {
+				run = product
+				continue
+			}
+		}
+		if run != nil {
+			// 确定字面量之后的子表达式以若干字面量开头时, 组合为更长的字面量
+			if product, ok := productLiterals(run, prefixLiterals(sub)); ok {
+				consider(product)
+			} else {
+				consider(run)
+			}
+			run = nil
+		}
+		if literals, ok := requiredLiterals(sub); ok {
+			consider(literals)
+		}
+		// 不确定的子表达式本身可能是确定字面量的开始
+		if literals, ok := exactLiterals(sub); ok {
+			run = literals
+		}
+	}
+	if run != nil {
+		consider(run)
+	}
+
+	return best, best != nil
+}
+
+// exactLiterals 返回仅由字面量、分组与分支组成的表达式可以匹配的全部字符串, 否则返回false
+func exactLiterals(regex *syntax.Regexp) ([]string, bool) {
+	switch regex.Op {
+	case syntax.OpLiteral:
+		return []string{string(regex.Rune)}, true
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpCharClass:
+		return classLiterals(regex)
+	case syntax.OpCapture:
+		return exactLiterals(regex.Sub[0])
+	case syntax.OpAlternate:
+		var literals []string
+		for _, sub := range regex.Sub {
+			subLiterals, ok := exactLiterals(sub)
+			if !ok {
+				return nil, false
+			}
+			literals = append(literals, subLiterals...)
+		}
+		return literals, len(literals) <= maxKeywordCount
+	case syntax.OpConcat:
+		literals := []string{""}
+		for _, sub := range regex.Sub {
+			subLiterals, ok := exactLiterals(sub)
+			if !ok {
+				return nil, false
+			}
+			if literals, ok = productLiterals(literals, subLiterals); !ok {
+				return nil, false
+			}
+		}
+		return literals, true
+	default:
+		return nil, false
+	}
+}
+
+// maxClassLiterals 展开为字面量的字符类最多包含的字符数
+const maxClassLiterals = 4
+
+// classLiterals 将字符数较少的字符类(如 [-_] 或忽略大小写的 [Kk])展开为单字符字面量
+func classLiterals(regex *syntax.Regexp) ([]string, bool) {
+	var literals []string
+	for i := 0; i+1 < len(regex.Rune); i += 2 {
+		if int(regex.Rune[i+1]-regex.Rune[i])+1+len(literals) > maxClassLiterals {
+			return nil, false
+		}
+		for r := regex.Rune[i]; r <= regex.Rune[i+1]; r++ {
+			literals = append(literals, string(r))
+		}
+	}
+	return literals, len(literals) > 0
+}
+
+// prefixLiterals 返回表达式任意匹配的开头必定是其中之一的字面量集合, 无法确定时返回空字符串
+func prefixLiterals(regex *syntax.Regexp) []string {
+	if literals, ok := exactLiterals(regex); ok {
+		return literals
+	}
+	switch regex.Op {
+	case syntax.OpCapture, syntax.OpPlus:
+		return prefixLiterals(regex.Sub[0])
+	case syntax.OpRepeat:
+		if regex.Min >= 1 {
+			return prefixLiterals(regex.Sub[0])
+		}
+	case syntax.OpConcat:
+		literals := []string{""}
+		for _, sub := range regex.Sub {
+			subLiterals, exact := exactLiterals(sub)
+			if !exact {
+				subLiterals = prefixLiterals(sub)
+			}
+			product, ok := productLiterals(literals, subLiterals)
+			if !ok {
+				return literals
+			}
+			literals = product
+			if !exact {
+				break
+			}
+		}
+		return literals
+	case syntax.OpAlternate:
+		var literals []string
+		for _, sub := range regex.Sub {
+			literals = append(literals, prefixLiterals(sub)...)
+		}
+		if len(literals) <= maxKeywordCount {
+			return literals
+		}
+	}
+	return []string{""}
+}
+
+// productLiterals 返回两组字面量依次连接的全部组合, 组合数超过上限时返回false
+func productLiterals(left, right []string) ([]string, bool) {
+	if len(left)*len(right) > maxKeywordCount {
+		return nil, false
+	}
+	product := make([]string, 0, len(left)*len(right))
+	for _, prefix := range left {
+		for _, suffix := range right {
+			product = append(product, prefix+suffix)
+		}
+	}
+	return product, true
+}
+
+// shortestLength 返回字面量集合中最短字面量的长度(字符数)
+func shortestLength(literals []string) int {
+	if len(literals) == 0 {
+		return 0
+	}
+	shortest := -1
+	for _, literal := range literals {
+		if length := len([]rune(literal)); shortest < 0 || length < shortest {
+			shortest = length
+		}
+	}
+	return shortest
+}
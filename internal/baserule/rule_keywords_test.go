@@ -0,0 +1,56 @@
+package baserule
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDeriveKeywords 测试从正则中提取必需字面量
+func TestDeriveKeywords(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{`jdbc:[a-z]+://\S+`, []string{"jdbc:"}},
+		{`(?i)(LTAI[a-z0-9]{12,20})`, []string{"ltai"}},
+		{`(bearer|basic)\s+[a-z0-9._-]+`, []string{"bearer", "basic"}},
+		{`\b(access)(|-|_)(key)\s*=\s*\w+`, []string{"accesskey", "access-key", "access_key"}},
+		{`(password){1,3}=\w+`, []string{"password"}},
+		{`[^0-9](10\.\d+|192\.168\.\d+)`, []string{"10.", "192.168."}},
+		// 无法保证每个匹配都包含的字面量
+		{`\d{17}[\dXx]`, nil},
+		{`(token)?=\w+`, nil},
+		{`(secret|\d+)=\w+`, nil},
+		{`(ab|cd)\d+`, nil},
+		{`\w+(?=px)`, nil},
+	}
+
+	for _, tc := range testCases {
+		actual := DeriveKeywords(tc.pattern)
+		if strings.Join(actual, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("DeriveKeywords(%q) = %q, expected %q", tc.pattern, actual, tc.expected)
+		}
+	}
+}
+
+// TestPrefilterKeywords 测试配置关键字优先于自动提取, Java 引擎规则不自动提取
+func TestPrefilterKeywords(t *testing.T) {
+	rule := Rule{FRegex: `jdbc:\S+`, Keywords: []string{" JDBC ", "DataSource", "jdbc"}}
+	for _, engine := range []RegexEngine{RegexEngineGo, RegexEngineJava} {
+		if actual := rule.PrefilterKeywords(engine); strings.Join(actual, ",") != "jdbc,datasource" {
+			t.Errorf("PrefilterKeywords(%s) = %q, expected configured keywords", engine, actual)
+		}
+	}
+
+	rule.Keywords = nil
+	if actual := rule.PrefilterKeywords(RegexEngineGo); strings.Join(actual, ",") != "jdbc:" {
+		t.Errorf("PrefilterKeywords(go) = %q, expected derived keywords", actual)
+	}
+	if actual := rule.PrefilterKeywords(RegexEngineJava); actual != nil {
+		t.Errorf("PrefilterKeywords(java) = %q, expected no derived keywords", actual)
+	}
+
+	if FoldKeyword("ſecret KEY") != "secret key" {
+		t.Errorf("FoldKeyword should fold long s and kelvin sign like (?i)")
+	}
+}
@@ -2,6 +2,12 @@ package baserule
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"github.com/winezer0/xutils/utils"
 	"privacycheck/internal/embeds"
 )
@@ -17,6 +23,192 @@ func LoadRulesYaml(configPath string) (*RuleConfig, error) {
 	return &ruleConfig, nil
 }
 
+// RuleConflict 同一规则(组名+规则名)被重复定义
+// 两次定义来自同一文件时 Previous 与 Current 相同, 两条规则都保留
+type RuleConflict struct {
+	Group    string // 规则组名称
+	Name     string // 规则名称
+	Previous string // 被覆盖的定义来源
+	Current  string // 生效的定义来源
+}
+
+// String 返回冲突描述
+func (c RuleConflict) String() string {
+	if c.Previous == c.Current {
+		return fmt.Sprintf("rule [%s: %s] is defined more than once in %s, all definitions are kept", c.Group, c.Name, c.Current)
+	}
+	return fmt.Sprintf("rule [%s: %s] defined in %s is overridden by %s", c.Group, c.Name, c.Previous, c.Current)
+}
+
+// ruleLoader 多规则文件加载与合并
+type ruleLoader struct {
+	config     RuleConfig
+	groupIndex map[string]int    // 组名 -> config.Rules 下标
+	ruleSource map[string]string // 组名+规则名 -> 定义来源
+	loading    map[string]bool   // 正在加载的文件, 用于检测循环引用
+	loaded     map[string]bool   // 已加载的文件, 避免重复加载
+	conflicts  []RuleConflict
+}
+
+// LoadRules 加载多个规则文件或目录并合并
+// 目录会递归加载其中的 .yaml/.yml 文件(按路径排序); 规则文件可以通过 include 引用其他文件或目录, 路径相对于当前文件
+// 合并优先级: 后加载的定义覆盖先加载的同组同名规则, 文件自身的规则覆盖其 include 的规则
+func LoadRules(paths []string) (*RuleConfig, []RuleConflict, error) {
+	loader := &ruleLoader{
+		groupIndex: make(map[string]int),
+		ruleSource: make(map[string]string),
+		loading:    make(map[string]bool),
+		loaded:     make(map[string]bool),
+	}
+
+	for _, path := range paths {
+		if err := loader.loadPath(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &loader.config, loader.conflicts, nil
+}
+
+// loadPath 加载文件、目录或通配符路径
+func (l *ruleLoader) loadPath(path string) error {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return fmt.Errorf("invalid rule path pattern %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no rule file matches pattern: %s", path)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if err := l.loadPath(match); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to access the rule path %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return l.loadFile(path)
+	}
+
+	files, err := findRuleFiles(path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := l.loadFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findRuleFiles 递归查找目录中的规则文件
+func findRuleFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk the rule directory %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadFile 加载单个规则文件, 先处理其 include 再合并自身规则
+func (l *ruleLoader) loadFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	if l.loading[absPath] {
+		return fmt.Errorf("circular include detected: %s", path)
+	}
+	if l.loaded[absPath] {
+		return nil
+	}
+	l.loading[absPath] = true
+	defer delete(l.loading, absPath)
+	l.config.Files = append(l.config.Files, path)
+
+	ruleConfig, err := LoadRulesYaml(path)
+	if err != nil {
+		return err
+	}
+
+	for _, include := range ruleConfig.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), includePath)
+		}
+		if err := l.loadPath(includePath); err != nil {
+			return fmt.Errorf("failed to include %s from %s: %w", include, path, err)
+		}
+	}
+
+	l.merge(ruleConfig.Rules, path)
+	l.loaded[absPath] = true
+	return nil
+}
+
+// merge 合并规则组, 其他文件中已定义的同组同名规则以后者为准并记录冲突
+// 同一文件中的同名规则与单文件加载时一样全部保留, 同样记录冲突
+func (l *ruleLoader) merge(groups []Rules, source string) {
+	for _, group := range groups {
+		index, ok := l.groupIndex[group.Group]
+		if !ok {
+			index = len(l.config.Rules)
+			l.groupIndex[group.Group] = index
+			l.config.Rules = append(l.config.Rules, Rules{Group: group.Group})
+		}
+
+		target := &l.config.Rules[index]
+		for _, rule := range group.Rule {
+			key := group.Group + "\x00" + rule.Name
+			previous, exists := l.ruleSource[key]
+			if !exists || previous == source {
+				if exists {
+					l.conflicts = append(l.conflicts, RuleConflict{Group: group.Group, Name: rule.Name, Previous: previous, Current: source})
+				}
+				target.Rule = append(target.Rule, rule)
+			} else {
+				l.conflicts = append(l.conflicts, RuleConflict{Group: group.Group, Name: rule.Name, Previous: previous, Current: source})
+				target.Rule = replaceRule(target.Rule, rule)
+			}
+			l.ruleSource[key] = source
+		}
+	}
+}
+
+// replaceRule 使用新定义替换第一条同名规则, 并移除之前定义的其他同名规则
+func replaceRule(rules []Rule, rule Rule) []Rule {
+	replaced := rules[:0]
+	found := false
+	for _, existing := range rules {
+		if existing.Name != rule.Name {
+			replaced = append(replaced, existing)
+		} else if !found {
+			replaced = append(replaced, rule)
+			found = true
+		}
+	}
+	return replaced
+}
+
 // CreateDefaultConfig 创建默认配置文件
 func CreateDefaultConfig(configPath string) error {
 	// 写入默认配置（fileutils.WriteFile会自动创建目录）
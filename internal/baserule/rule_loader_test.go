@@ -0,0 +1,164 @@
+package baserule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRuleFile 写入测试用规则文件
+func writeRuleFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
+
+// TestLoadRulesWithIncludeAndOverride 测试 include 与多文件合并优先级
+func TestLoadRulesWithIncludeAndOverride(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRuleFile(t, filepath.Join(dir, "base", "people.yaml"), `
+rules:
+  - group: People
+    rule:
+      - name: Email
+        f_regex: base-email
+        loaded: true
+      - name: Phone
+        f_regex: base-phone
+        loaded: true
+`)
+	writeRuleFile(t, filepath.Join(dir, "base", "system.yml"), `
+rules:
+  - group: System
+    rule:
+      - name: Internal IP
+        f_regex: base-ip
+        loaded: true
+`)
+	writeRuleFile(t, filepath.Join(dir, "client", "client.yaml"), `
+include:
+  - ../base
+rules:
+  - group: People
+    rule:
+      - name: Email
+        f_regex: client-email
+        loaded: false
+      - name: Passport
+        f_regex: client-passport
+        loaded: true
+`)
+	writeRuleFile(t, filepath.Join(dir, "local.yaml"), `
+rules:
+  - group: System
+    rule:
+      - name: Internal IP
+        f_regex: local-ip
+        loaded: true
+`)
+
+	config, conflicts, err := LoadRules([]string{
+		filepath.Join(dir, "client", "client.yaml"),
+		filepath.Join(dir, "local.yaml"),
+	})
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+
+	if len(config.Rules) != 2 || config.Rules[0].Group != "People" || config.Rules[1].Group != "System" {
+		t.Fatalf("unexpected groups: %+v", config.Rules)
+	}
+
+	people := config.Rules[0].Rule
+	if len(people) != 3 {
+		t.Fatalf("expected 3 people rules, got %d", len(people))
+	}
+	if people[0].Name != "Email" || people[0].FRegex != "client-email" || people[0].Loaded {
+		t.Errorf("expected client override for Email in place, got %+v", people[0])
+	}
+	if people[2].Name != "Passport" {
+		t.Errorf("expected new rule appended, got %+v", people[2])
+	}
+	if config.Rules[1].Rule[0].FRegex != "local-ip" {
+		t.Errorf("expected later file to override Internal IP, got %s", config.Rules[1].Rule[0].FRegex)
+	}
+
+	if len(config.Files) != 4 || !strings.HasSuffix(config.Files[0], "client.yaml") || !strings.HasSuffix(config.Files[3], "local.yaml") {
+		t.Errorf("unexpected loaded files: %v", config.Files)
+	}
+
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d: %v", len(conflicts), conflicts)
+	}
+	if !strings.HasSuffix(conflicts[0].Previous, "people.yaml") || !strings.HasSuffix(conflicts[0].Current, "client.yaml") {
+		t.Errorf("unexpected conflict sources: %s", conflicts[0])
+	}
+}
+
+// TestLoadRulesCircularInclude 测试循环引用检测
+func TestLoadRulesCircularInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\nrules: []\n")
+	writeRuleFile(t, filepath.Join(dir, "b.yaml"), "include: [a.yaml]\nrules: []\n")
+
+	if _, _, err := LoadRules([]string{filepath.Join(dir, "a.yaml")}); err == nil || !strings.Contains(err.Error(), "circular include") {
+		t.Errorf("expected circular include error, got %v", err)
+	}
+
+	if _, _, err := LoadRules([]string{filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Errorf("expected error for missing rule file")
+	}
+}
+
+// TestLoadRulesSameNameInOneFile 测试同一文件中的同名规则全部保留并报告冲突, 其他文件的定义整体覆盖
+func TestLoadRulesSameNameInOneFile(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, filepath.Join(dir, "a.yaml"), `
+rules:
+  - group: Keys
+    rule:
+      - name: Token
+        f_regex: token-a1
+      - name: Token
+        f_regex: token-a2
+      - name: Secret
+        f_regex: secret-a
+`)
+	writeRuleFile(t, filepath.Join(dir, "b.yaml"), `
+rules:
+  - group: Keys
+    rule:
+      - name: Token
+        f_regex: token-b
+`)
+
+	config, conflicts, err := LoadRules([]string{filepath.Join(dir, "a.yaml")})
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+	if rules := config.Rules[0].Rule; len(rules) != 3 || rules[1].FRegex != "token-a2" {
+		t.Fatalf("expected both same-named rules to be kept, got %+v", rules)
+	}
+	// 同一文件中的重复定义同样作为冲突报告
+	if len(conflicts) != 1 || conflicts[0].Previous != conflicts[0].Current || !strings.Contains(conflicts[0].String(), "defined more than once") {
+		t.Errorf("expected 1 same-file conflict, got %v", conflicts)
+	}
+
+	config, conflicts, err = LoadRules([]string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")})
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+	rules := config.Rules[0].Rule
+	if len(rules) != 2 || rules[0].FRegex != "token-b" || rules[1].Name != "Secret" {
+		t.Errorf("expected later file to replace both Token rules in place, got %+v", rules)
+	}
+	if len(conflicts) != 2 || conflicts[1].Previous == conflicts[1].Current {
+		t.Errorf("expected the same-file conflict and 1 override, got %v", conflicts)
+	}
+}
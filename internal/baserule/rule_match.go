@@ -0,0 +1,67 @@
+package baserule
+
+import "fmt"
+
+// ExtractValue 根据 secret_group 从匹配结果中提取值, 分组不存在时返回整个匹配
+func (r *Rule) ExtractValue(match MatchResult) string {
+	value, _ := r.ExtractSpan(match)
+	return value
+}
+
+// ExtractSpan 返回提取值及其在原文中的字节位置, 提取的分组未参与匹配时位置为 -1
+func (r *Rule) ExtractSpan(match MatchResult) (string, int) {
+	if r.SecretGroup > 0 {
+		groups := match.Groups()
+		if r.SecretGroup < len(groups) {
+			return groups[r.SecretGroup].String(), groups[r.SecretGroup].Index()
+		}
+	}
+	return match.String(), match.Index()
+}
+
+// MatchSample 测试 sample_code 中是否存在满足规则全部检查条件的匹配
+func (r *Rule) MatchSample(matcher RegexMatcher) (bool, error) {
+	allowlist, err := r.Allowlist.Compile()
+	if err != nil {
+		return false, err
+	}
+
+	match, err := matcher.FindStringMatch(r.SampleCode)
+	for match != nil && err == nil {
+		value := r.ExtractValue(match)
+		if _, ok := r.CheckEntropy(value); ok && r.CheckValidator(value) && !allowlist.Allowed(value, "") {
+			return true, nil
+		}
+		match, err = match.FindNextMatch()
+	}
+	return false, err
+}
+
+// ScanPattern 返回扫描时实际编译的正则(忽略大小写 + 多行模式)
+func (r *Rule) ScanPattern() string {
+	return "(?m)(?i)" + r.FRegex
+}
+
+// CompileMatcher 按扫描时的方式编译规则正则
+// nfa/dfa(HAE规则)转换为java引擎; 引擎为空时先尝试Go引擎, 失败则使用Java引擎
+func (r *Rule) CompileMatcher() (RegexMatcher, error) {
+	pattern := r.ScanPattern()
+	engine := r.Engine
+	if engine == "dfa" || engine == "nfa" {
+		engine = string(RegexEngineJava)
+	}
+
+	if engine == "" {
+		matcher, err := TryCompileWithFallback(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regex with fallback: %w", err)
+		}
+		return matcher, nil
+	}
+
+	matcher, err := NewRegexMatcher(pattern, RegexEngine(engine))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile regex with specified engine %s: %w", engine, err)
+	}
+	return matcher, nil
+}
@@ -0,0 +1,115 @@
+package baserule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+)
+
+// RuleOverride 单条规则覆盖配置
+// 通过 id 或 group + name 定位规则, group 为空时匹配所有组中的同名规则
+type RuleOverride struct {
+	ID        string                 `yaml:"id" json:"id"`               // 规则ID
+	Group     string                 `yaml:"group" json:"group"`         // 规则组名称
+	Name      string                 `yaml:"name" json:"name"`           // 规则名称
+	Set       map[string]interface{} `yaml:"set" json:"set"`             // 需要修改的规则字段, 键名与规则文件一致
+	Allowlist Allowlist              `yaml:"allowlist" json:"allowlist"` // 追加的白名单条目
+}
+
+// RuleOverrideConfig 规则覆盖文件
+type RuleOverrideConfig struct {
+	Overrides []RuleOverride `yaml:"overrides" json:"overrides"`
+}
+
+// LoadRuleOverrides 加载规则覆盖文件
+func LoadRuleOverrides(overridePath string) ([]RuleOverride, error) {
+	var overrideConfig RuleOverrideConfig
+	if err := utils.LoadYAML(overridePath, &overrideConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the override file:%s error: %w", overridePath, err)
+	}
+	return overrideConfig.Overrides, nil
+}
+
+// matches 判断覆盖配置是否作用于指定规则
+func (o *RuleOverride) matches(group string, rule *Rule) bool {
+	if o.ID != "" {
+		return o.ID == rule.ID
+	}
+	return o.Name == rule.Name && (o.Group == "" || o.Group == group)
+}
+
+// String 返回覆盖配置的定位描述
+func (o *RuleOverride) String() string {
+	if o.ID != "" {
+		return fmt.Sprintf("id=%s", o.ID)
+	}
+	return fmt.Sprintf("group=%s, name=%s", o.Group, o.Name)
+}
+
+// apply 将覆盖配置应用到规则
+// 字段修改通过 JSON 序列化合并实现, 规则的 json 标签与 yaml 标签一致, 未知字段会返回错误
+func (o *RuleOverride) apply(rule *Rule) error {
+	if len(o.Set) > 0 {
+		data, err := json.Marshal(rule)
+		if err != nil {
+			return err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+
+		for key, value := range o.Set {
+			if _, ok := fields[key]; !ok {
+				return fmt.Errorf("override [%s] sets unknown rule field: %s", o, key)
+			}
+			fields[key] = value
+		}
+
+		data, err = json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("override [%s] has invalid values: %w", o, err)
+		}
+		var patched Rule
+		if err := json.Unmarshal(data, &patched); err != nil {
+			return fmt.Errorf("override [%s] has invalid values: %w", o, err)
+		}
+		*rule = patched
+	}
+
+	rule.Allowlist.Append(o.Allowlist)
+	return nil
+}
+
+// ApplyOverrides 按顺序将覆盖配置应用到规则, 未命中任何规则的覆盖配置会输出警告
+func (c *RuleConfig) ApplyOverrides(overrides []RuleOverride) error {
+	for i := range overrides {
+		override := &overrides[i]
+		if override.ID == "" && override.Name == "" {
+			return fmt.Errorf("override #%d must specify id or name", i+1)
+		}
+
+		matched := 0
+		for g := range c.Rules {
+			group := &c.Rules[g]
+			for r := range group.Rule {
+				if !override.matches(group.Group, &group.Rule[r]) {
+					continue
+				}
+				if err := override.apply(&group.Rule[r]); err != nil {
+					return err
+				}
+				matched++
+			}
+		}
+
+		if matched == 0 {
+			logging.Warnf("override [%s] does not match any rule", override)
+		} else {
+			logging.Infof("override [%s] applied to %d rules", override, matched)
+		}
+	}
+	return nil
+}
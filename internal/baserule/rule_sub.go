@@ -2,6 +2,7 @@ package baserule
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/winezer0/xutils/logging"
@@ -14,6 +15,9 @@ func (c *RuleConfig) ValidateRules() error {
 	var invalidRules []string
 	validRulesCount := 0
 
+	// 规则ID需要全局唯一, 包括未启用的规则
+	invalidRules = append(invalidRules, c.validateRuleIDs()...)
+
 	for _, group := range c.Rules {
 		for _, rule := range group.Rule {
 			// 检查loaded字段(默认为True)
@@ -34,6 +38,48 @@ func (c *RuleConfig) ValidateRules() error {
 				continue
 			}
 
+			// 验证严重等级和置信度
+			if rule.Severity != "" && !IsValidSeverity(rule.Severity) {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [severity] %s must be one of %s", group.Group, rule.Name, rule.Severity, strings.Join(SeverityLevels, ",")))
+				continue
+			}
+			if rule.Confidence != "" && !IsValidConfidence(rule.Confidence) {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [confidence] %s must be one of %s", group.Group, rule.Name, rule.Confidence, strings.Join(ConfidenceLevels, ",")))
+				continue
+			}
+
+			// 验证熵检测配置
+			switch strings.ToLower(rule.EntropyCharset) {
+			case "", EntropyCharsetBase64, EntropyCharsetHex:
+			default:
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [entropy_charset] must be base64 or hex", group.Group, rule.Name))
+				continue
+			}
+			if rule.SecretGroup < 0 || rule.Entropy < 0 {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [secret_group] and [entropy] must not be negative", group.Group, rule.Name))
+				continue
+			}
+
+			if rule.Validator != "" && !IsValidatorSupported(rule.Validator) {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [validator] %s is not supported, available: %s", group.Group, rule.Name, rule.Validator, strings.Join(ValidatorNames(), ",")))
+				continue
+			}
+
+			if rule.Analyzer != "" && !IsAnalyzerSupported(rule.Analyzer) {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [analyzer] %s is not supported, available: %s", group.Group, rule.Name, rule.Analyzer, strings.Join(AnalyzerNames(), ",")))
+				continue
+			}
+
+			if len(rule.Keywords) > 0 && len(normalizePrefilterKeywords(rule.Keywords)) != len(rule.Keywords) {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [keywords] must not contain empty or duplicate keywords", group.Group, rule.Name))
+				continue
+			}
+
+			if _, err := rule.Allowlist.Compile(); err != nil {
+				invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: The rule [allowlist] is invalid: %v", group.Group, rule.Name, err))
+				continue
+			}
+
 			// 验证正则表达式，使用回退机制
 			matcher, err := TryCompileWithFallback(rule.FRegex)
 			if err != nil {
@@ -44,7 +90,7 @@ func (c *RuleConfig) ValidateRules() error {
 					logging.Warnf("Rule Group %s, Rule %s: No sample_code provided for testing", group.Group, rule.Name)
 				} else {
 					// 测试正则是否能够匹配 SampleCode
-					match, err := matcher.MatchString(rule.SampleCode)
+					match, err := rule.MatchSample(matcher)
 					if err != nil {
 						invalidRules = append(invalidRules, fmt.Sprintf("Rule Group %s, Rule %s: Error matching sample_code: %v", group.Group, rule.Name, err))
 					} else if !match {
@@ -67,22 +113,81 @@ func (c *RuleConfig) ValidateRules() error {
 	return nil
 }
 
-// FilterRules 过滤规则
-func (c *RuleConfig) FilterRules(filterGroups, filterNames []string, sensitiveOnly bool) RuleMap {
-	result := make(RuleMap)
+// ruleIDPattern 规则ID允许的字符
+var ruleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
 
-	// 转换过滤条件为小写
-	var lowerFilterGroups, lowerFilterNames []string
-	for _, group := range filterGroups {
-		if strings.TrimSpace(group) != "" {
-			lowerFilterGroups = append(lowerFilterGroups, strings.ToLower(strings.TrimSpace(group)))
+// validateRuleIDs 检查规则ID格式以及是否重复
+func (c *RuleConfig) validateRuleIDs() []string {
+	var invalidRules []string
+	seen := make(map[string]string)
+
+	for _, group := range c.Rules {
+		for _, rule := range group.Rule {
+			if rule.ID == "" {
+				continue
+			}
+			location := fmt.Sprintf("Rule Group %s, Rule %s", group.Group, rule.Name)
+			if !ruleIDPattern.MatchString(rule.ID) {
+				invalidRules = append(invalidRules, fmt.Sprintf("%s: The rule [id] %s may only contain letters, digits, '.', '_' and '-'", location, rule.ID))
+				continue
+			}
+			if previous, ok := seen[rule.ID]; ok {
+				invalidRules = append(invalidRules, fmt.Sprintf("%s: The rule [id] %s is already used by %s", location, rule.ID, previous))
+				continue
+			}
+			seen[rule.ID] = location
 		}
 	}
-	for _, name := range filterNames {
-		if strings.TrimSpace(name) != "" {
-			lowerFilterNames = append(lowerFilterNames, strings.ToLower(strings.TrimSpace(name)))
+
+	return invalidRules
+}
+
+// FilterOptions 规则过滤条件
+type FilterOptions struct {
+	Groups        []string // 规则组名称关键字
+	Names         []string // 规则名称关键字
+	SensitiveOnly bool     // 仅保留敏感信息规则
+	MinSeverity   string   // 最低严重等级
+	Tags          []string // 仅保留包含任一标签(或分类)的规则
+	ExcludeTags   []string // 排除包含任一标签(或分类)的规则
+}
+
+// normalizeKeywords 去除空白并转换为小写
+func normalizeKeywords(keywords []string) []string {
+	var result []string
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword) != "" {
+			result = append(result, strings.ToLower(strings.TrimSpace(keyword)))
 		}
 	}
+	return result
+}
+
+// HasAnyTag 判断规则的标签或分类是否包含任一指定标签(忽略大小写)
+func (r *Rule) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(r.Category, tag) {
+			return true
+		}
+		for _, ruleTag := range r.Tags {
+			if strings.EqualFold(ruleTag, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterRules 过滤规则
+func (c *RuleConfig) FilterRules(options FilterOptions) RuleMap {
+	result := make(RuleMap)
+	minSeverityRank := SeverityRank(options.MinSeverity)
+
+	// 转换过滤条件为小写
+	lowerFilterGroups := normalizeKeywords(options.Groups)
+	lowerFilterNames := normalizeKeywords(options.Names)
+	filterTags := normalizeKeywords(options.Tags)
+	excludeTags := normalizeKeywords(options.ExcludeTags)
 
 	for _, group := range c.Rules {
 		// 按照group_name进行过滤
@@ -107,7 +212,20 @@ func (c *RuleConfig) FilterRules(filterGroups, filterNames []string, sensitiveOn
 			}
 
 			// 仅敏感模式下排除非敏感信息的规则
-			if sensitiveOnly && !rule.Sensitive {
+			if options.SensitiveOnly && !rule.Sensitive {
+				continue
+			}
+
+			// 按严重等级过滤
+			if minSeverityRank > 0 && SeverityRank(rule.GetSeverity()) < minSeverityRank {
+				continue
+			}
+
+			// 按标签过滤
+			if len(filterTags) > 0 && !rule.HasAnyTag(filterTags) {
+				continue
+			}
+			if len(excludeTags) > 0 && rule.HasAnyTag(excludeTags) {
 				continue
 			}
 
@@ -131,6 +249,10 @@ func (c *RuleConfig) FilterRules(filterGroups, filterNames []string, sensitiveOn
 				rule.ContextRight = 50
 			}
 
+			// 填充严重等级和置信度的默认值
+			rule.Severity = rule.GetSeverity()
+			rule.Confidence = rule.GetConfidence()
+
 			filteredRules = append(filteredRules, rule)
 		}
 
@@ -0,0 +1,50 @@
+package baserule
+
+import (
+	"testing"
+)
+
+// TestFilterRulesByTags 测试按标签和分类过滤规则
+func TestFilterRulesByTags(t *testing.T) {
+	config := &RuleConfig{
+		Rules: []Rules{
+			{
+				Group: "test",
+				Rule: []Rule{
+					{Name: "Email", FRegex: "@", Loaded: true, Category: "contact_info", Tags: []string{"pipl", "gdpr"}},
+					{Name: "Bank Card", FRegex: "62", Loaded: true, Category: "financial", Tags: []string{"PCI-DSS"}},
+					{Name: "Internal IP", FRegex: "10\\.", Loaded: true, Category: "infrastructure"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		options  FilterOptions
+		expected []string
+	}{
+		{FilterOptions{Tags: []string{"pci-dss"}}, []string{"Bank Card"}},
+		{FilterOptions{Tags: []string{"Infrastructure"}}, []string{"Internal IP"}},
+		{FilterOptions{Tags: []string{"pipl", "pci-dss"}}, []string{"Email", "Bank Card"}},
+		{FilterOptions{ExcludeTags: []string{"gdpr"}}, []string{"Bank Card", "Internal IP"}},
+		{FilterOptions{Tags: []string{"pipl"}, ExcludeTags: []string{"contact_info"}}, nil},
+	}
+
+	for _, tc := range testCases {
+		filtered := config.FilterRules(tc.options)
+		var names []string
+		for _, rule := range filtered["test"] {
+			names = append(names, rule.Name)
+		}
+		if len(names) != len(tc.expected) {
+			t.Errorf("FilterRules(%+v) = %v, expected %v", tc.options, names, tc.expected)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.expected[i] {
+				t.Errorf("FilterRules(%+v) = %v, expected %v", tc.options, names, tc.expected)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package baserule
+
+import (
+	"strings"
+)
+
+// 严重等级, 由低到高
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// 置信度
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// SeverityLevels 按由低到高排列的严重等级
+var SeverityLevels = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// ConfidenceLevels 按由低到高排列的置信度
+var ConfidenceLevels = []string{ConfidenceLow, ConfidenceMedium, ConfidenceHigh}
+
+// levelRank 返回等级在列表中的序号, 不存在时返回 -1
+func levelRank(levels []string, level string) int {
+	level = strings.ToLower(strings.TrimSpace(level))
+	for i, l := range levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// SeverityRank 返回严重等级序号(info=0 ... critical=4), 非法等级返回 -1
+func SeverityRank(severity string) int {
+	return levelRank(SeverityLevels, severity)
+}
+
+// IsValidSeverity 判断严重等级是否合法
+func IsValidSeverity(severity string) bool {
+	return SeverityRank(severity) >= 0
+}
+
+// ConfidenceRank 返回置信度序号(low=0 ... high=2), 非法置信度返回 -1
+func ConfidenceRank(confidence string) int {
+	return levelRank(ConfidenceLevels, confidence)
+}
+
+// IsValidConfidence 判断置信度是否合法
+func IsValidConfidence(confidence string) bool {
+	return ConfidenceRank(confidence) >= 0
+}
+
+// GetSeverity 返回规则的严重等级
+// 未配置 severity 时兼容旧配置: sensitive 为 true 映射为 high, 否则为 info
+func (r *Rule) GetSeverity() string {
+	if r.Severity != "" {
+		return strings.ToLower(strings.TrimSpace(r.Severity))
+	}
+	if r.Sensitive {
+		return SeverityHigh
+	}
+	return SeverityInfo
+}
+
+// GetConfidence 返回规则的置信度, 未配置时为 medium
+func (r *Rule) GetConfidence() string {
+	if r.Confidence != "" {
+		return strings.ToLower(strings.TrimSpace(r.Confidence))
+	}
+	return ConfidenceMedium
+}
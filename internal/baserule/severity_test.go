@@ -0,0 +1,62 @@
+package baserule
+
+import (
+	"testing"
+)
+
+// TestRuleGetSeverity 测试严重等级与 sensitive 的默认映射
+func TestRuleGetSeverity(t *testing.T) {
+	testCases := []struct {
+		rule     Rule
+		expected string
+	}{
+		{Rule{Sensitive: true}, SeverityHigh},
+		{Rule{Sensitive: false}, SeverityInfo},
+		{Rule{Sensitive: true, Severity: "Low"}, SeverityLow},
+		{Rule{Severity: SeverityCritical}, SeverityCritical},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.rule.GetSeverity(); got != tc.expected {
+			t.Errorf("GetSeverity(%+v) = %s, expected %s", tc.rule, got, tc.expected)
+		}
+	}
+
+	if got := (&Rule{}).GetConfidence(); got != ConfidenceMedium {
+		t.Errorf("expected default confidence medium, got %s", got)
+	}
+}
+
+// TestFilterRulesByMinSeverity 测试按最低严重等级过滤规则
+func TestFilterRulesByMinSeverity(t *testing.T) {
+	config := &RuleConfig{
+		Rules: []Rules{
+			{
+				Group: "test",
+				Rule: []Rule{
+					{Name: "Internal IP", FRegex: "10\\.0\\.0\\.1", Loaded: true, Severity: SeverityLow},
+					{Name: "Password", FRegex: "password", Loaded: true, Sensitive: true},
+					{Name: "Cloud Key", FRegex: "LTAI", Loaded: true, Severity: SeverityCritical},
+				},
+			},
+		},
+	}
+
+	filtered := config.FilterRules(FilterOptions{MinSeverity: SeverityHigh})
+	if count := filtered.CountRules(); count != 2 {
+		t.Fatalf("expected 2 rules, got %d", count)
+	}
+	for _, rule := range filtered["test"] {
+		if rule.Name == "Internal IP" {
+			t.Errorf("expected low severity rule to be filtered")
+		}
+		if rule.Name == "Password" && rule.Severity != SeverityHigh {
+			t.Errorf("expected sensitive rule severity to be filled as high, got %s", rule.Severity)
+		}
+	}
+
+	all := config.FilterRules(FilterOptions{})
+	if count := all.CountRules(); count != 3 {
+		t.Errorf("expected all rules without min severity, got %d", count)
+	}
+}
@@ -0,0 +1,230 @@
+package baserule
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 内置校验器名称
+const (
+	ValidatorIDCard = "idcard" // GB 11643 居民身份证号码
+	ValidatorLuhn   = "luhn"   // Luhn 银行卡号
+	ValidatorUSCC   = "uscc"   // GB 32100 统一社会信用代码
+	ValidatorIBAN   = "iban"   // ISO 13616 国际银行账号
+	ValidatorIPv4   = "ipv4"   // IPv4 地址
+)
+
+// ValidatorFunc 校验提取值是否合法
+type ValidatorFunc func(value string) bool
+
+// validators 内置校验器
+var validators = map[string]ValidatorFunc{
+	ValidatorIDCard: ValidateIDCard,
+	ValidatorLuhn:   ValidateLuhn,
+	ValidatorUSCC:   ValidateUSCC,
+	ValidatorIBAN:   ValidateIBAN,
+	ValidatorIPv4:   ValidateIPv4,
+}
+
+// ValidatorNames 返回所有内置校验器名称
+func ValidatorNames() []string {
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValidatorSupported 判断校验器是否存在
+func IsValidatorSupported(name string) bool {
+	_, ok := validators[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// CheckValidator 使用规则配置的校验器检查提取值, 未配置校验器时始终通过
+func (r *Rule) CheckValidator(value string) bool {
+	if r.Validator == "" {
+		return true
+	}
+	validator, ok := validators[strings.ToLower(strings.TrimSpace(r.Validator))]
+	if !ok {
+		return false
+	}
+	return validator(value)
+}
+
+// trimValue 去除提取值首尾的非字母数字字符(HAE规则常以 [^0-9] 作为边界)
+func trimValue(value string) string {
+	return strings.TrimFunc(value, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+// compactValue 去除提取值中的空白和连字符
+func compactValue(value string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) || c == '-' {
+			return -1
+		}
+		return c
+	}, trimValue(value))
+}
+
+// isDigits 判断字符串是否全部为数字
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// idCardProvinces 身份证前两位的省级行政区划代码
+var idCardProvinces = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true,
+	"21": true, "22": true, "23": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "36": true, "37": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true,
+	"50": true, "51": true, "52": true, "53": true, "54": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true,
+	"71": true, "81": true, "82": true, "83": true, "91": true,
+}
+
+// idCardWeights GB 11643 校验码加权因子
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// ValidateIDCard 校验居民身份证号码的行政区划、出生日期和校验码
+// 15位旧号码不含校验码, 仅校验行政区划和出生日期
+func ValidateIDCard(value string) bool {
+	id := strings.ToUpper(trimValue(value))
+
+	var birth string
+	switch len(id) {
+	case 15:
+		if !isDigits(id) {
+			return false
+		}
+		birth = "19" + id[6:12]
+	case 18:
+		if !isDigits(id[:17]) {
+			return false
+		}
+		birth = id[6:14]
+	default:
+		return false
+	}
+
+	if !idCardProvinces[id[:2]] {
+		return false
+	}
+
+	birthDate, err := time.Parse("20060102", birth)
+	if err != nil || birthDate.Year() < 1900 || birthDate.After(time.Now()) {
+		return false
+	}
+
+	if len(id) == 15 {
+		return true
+	}
+
+	sum := 0
+	for i, weight := range idCardWeights {
+		sum += int(id[i]-'0') * weight
+	}
+	return "10X98765432"[sum%11] == id[17]
+}
+
+// ValidateLuhn 使用 Luhn 算法校验银行卡号
+func ValidateLuhn(value string) bool {
+	number := compactValue(value)
+	if len(number) < 12 || len(number) > 19 || !isDigits(number) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// usccChars GB 32100 统一社会信用代码字符集(不含 I/O/Z/S/V)
+const usccChars = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+// usccWeights GB 32100 校验码加权因子
+var usccWeights = []int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// ValidateUSCC 校验统一社会信用代码的字符集和校验码
+func ValidateUSCC(value string) bool {
+	code := strings.ToUpper(trimValue(value))
+	if len(code) != 18 {
+		return false
+	}
+
+	sum := 0
+	for i, weight := range usccWeights {
+		index := strings.IndexByte(usccChars, code[i])
+		if index < 0 {
+			return false
+		}
+		sum += index * weight
+	}
+
+	check := (31 - sum%31) % 31
+	return usccChars[check] == code[17]
+}
+
+// ValidateIBAN 使用 ISO 7064 mod-97 校验国际银行账号
+func ValidateIBAN(value string) bool {
+	iban := strings.ToUpper(compactValue(value))
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	if iban[0] < 'A' || iban[0] > 'Z' || iban[1] < 'A' || iban[1] > 'Z' || !isDigits(iban[2:4]) {
+		return false
+	}
+
+	// 将前4位移至末尾, 字母转换为数字(A=10 ... Z=35), 逐位计算余数
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// ValidateIPv4 校验IPv4地址各段取值范围
+func ValidateIPv4(value string) bool {
+	address := strings.TrimFunc(value, func(c rune) bool {
+		return c != '.' && (c < '0' || c > '9')
+	})
+	if strings.Count(address, ".") != 3 {
+		return false
+	}
+	for _, part := range strings.Split(address, ".") {
+		// 拒绝带前导零的段, 避免误报版本号等内容
+		if part == "" || len(part) > 1 && part[0] == '0' {
+			return false
+		}
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() != nil
+}
@@ -0,0 +1,67 @@
+package baserule
+
+import (
+	"testing"
+)
+
+// TestValidators 测试内置校验器
+func TestValidators(t *testing.T) {
+	testCases := []struct {
+		validator string
+		value     string
+		expected  bool
+	}{
+		// GB 11643 身份证
+		{ValidatorIDCard, "110101199001011237", true},
+		{ValidatorIDCard, " 440304198506152715\n", true},
+		{ValidatorIDCard, "110101199001011234", false}, // 校验码错误
+		{ValidatorIDCard, "990101199001011237", false}, // 行政区划错误
+		{ValidatorIDCard, "110101199002301235", false}, // 日期不存在
+		{ValidatorIDCard, "110101900101123", true},     // 15位旧号码
+		{ValidatorIDCard, "20231012153045123", false},  // 长度错误的订单号
+
+		// Luhn 银行卡号
+		{ValidatorLuhn, "6222021234567890128", true},
+		{ValidatorLuhn, "4111 1111 1111 1111", true},
+		{ValidatorLuhn, "6222021234567890123", false},
+		{ValidatorLuhn, "12345", false},
+
+		// GB 32100 统一社会信用代码
+		{ValidatorUSCC, "91350100M000100Y43", true},
+		{ValidatorUSCC, "91350100M000100Y44", false},
+		{ValidatorUSCC, "91350100M000100I43", false}, // 非法字符
+
+		// IBAN
+		{ValidatorIBAN, "GB82 WEST 1234 5698 7654 32", true},
+		{ValidatorIBAN, "DE89370400440532013000", true},
+		{ValidatorIBAN, "DE89370400440532013001", false},
+
+		// IPv4
+		{ValidatorIPv4, " 192.168.1.100", true},
+		{ValidatorIPv4, "10.0.0.1", true},
+		{ValidatorIPv4, "10.0.0.256", false},
+		{ValidatorIPv4, "10.01.0.1", false},
+	}
+
+	for _, tc := range testCases {
+		rule := Rule{Validator: tc.validator}
+		if got := rule.CheckValidator(tc.value); got != tc.expected {
+			t.Errorf("validator %s(%q) = %t, expected %t", tc.validator, tc.value, got, tc.expected)
+		}
+	}
+}
+
+// TestUnknownValidator 测试未知校验器
+func TestUnknownValidator(t *testing.T) {
+	if IsValidatorSupported("md5") {
+		t.Errorf("expected md5 validator to be unsupported")
+	}
+	if !IsValidatorSupported(" Luhn ") {
+		t.Errorf("expected validator name to be case insensitive")
+	}
+
+	rule := Rule{Validator: "md5"}
+	if rule.CheckValidator("anything") {
+		t.Errorf("expected unknown validator to reject values")
+	}
+}
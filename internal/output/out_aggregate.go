@@ -0,0 +1,193 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/scanner"
+)
+
+// Location 结果出现的位置
+type Location struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"line_number"`
+	Position   int    `json:"position"`
+	Context    string `json:"context,omitempty"`
+}
+
+// AggregatedResult 按(规则, 规范化匹配值)聚合的结果
+type AggregatedResult struct {
+	Group       string   `json:"group"`
+	RuleID      string   `json:"rule_id,omitempty"`
+	RuleName    string   `json:"rule_name"`
+	Match       string   `json:"match"`                  // 规范化后的匹配值
+	Analysis    string   `json:"analysis,omitempty"`     // 规则分析器的分析结果类型
+	DecodeChain string   `json:"decode_chain,omitempty"` // 解码链, 解码后的结果与直接匹配的结果分别聚合
+	Encoded     string   `json:"encoded,omitempty"`      // 最外层编码内容(过长时截断)
+	Sensitive   bool     `json:"sensitive"`
+	Severity    string   `json:"severity,omitempty"`
+	Confidence  string   `json:"confidence,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	References  []string `json:"references,omitempty"`
+	Entropy     float64  `json:"entropy,omitempty"`
+	Count       int      `json:"count"`      // 出现次数
+	FileCount   int      `json:"file_count"` // 涉及的文件数量
+	Files       []string `json:"files"`      // 涉及的文件(按路径排序)
+	First       Location `json:"first"`      // 按文件路径与偏移排序后的第一次出现
+	Last        Location `json:"last"`       // 按文件路径与偏移排序后的最后一次出现
+}
+
+// aggregateHeaders 聚合结果的CSV表头
+var aggregateHeaders = []string{
+	"group", "rule_id", "rule_name", "match", "analysis", "decode_chain", "encoded", "sensitive", "severity", "confidence", "category", "tags",
+	"entropy", "count", "file_count", "files", "first_file", "first_line", "first_position", "last_file", "last_line", "last_position",
+}
+
+// normalizeMatch 规范化匹配值: 去除首尾空白、引号与括号, 还原转义的斜杠
+func (p *Output) normalizeMatch(match string) string {
+	return p.stripString(strings.TrimSpace(match))
+}
+
+// findingKey 返回结果按(规则, 分析结果类型, 解码链与编码内容, 规范化匹配值)去重的标识
+func (p *Output) findingKey(result scanner.ScanResult) string {
+	return strings.Join([]string{result.Group, result.RuleID, result.RuleName, result.Analysis, result.DecodeChain, result.Encoded, p.normalizeMatch(result.Match)}, "\x00")
+}
+
+// sortByLocation 按文件路径与偏移排序结果(扫描结果的文件顺序不固定), 返回排序后的副本
+func sortByLocation(results []scanner.ScanResult) []scanner.ScanResult {
+	sorted := make([]scanner.ScanResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Position < sorted[j].Position
+	})
+	return sorted
+}
+
+// dedupeByFile 同一文件中相同规则与规范化匹配值的结果只保留第一次出现
+func (p *Output) dedupeByFile(results []scanner.ScanResult) []scanner.ScanResult {
+	var deduped []scanner.ScanResult
+	seen := make(map[string]bool)
+	for _, result := range sortByLocation(results) {
+		key := result.File + "\x00" + p.findingKey(result)
+		if !seen[key] {
+			seen[key] = true
+			deduped = append(deduped, result)
+		}
+	}
+
+	logging.Infof("per-file deduplication: %d -> %d", len(results), len(deduped))
+	return deduped
+}
+
+// aggregateResults 按(规则, 规范化匹配值)聚合结果, 按出现次数由高到低排序
+func (p *Output) aggregateResults(results []scanner.ScanResult) []AggregatedResult {
+	var aggregated []*AggregatedResult
+	index := make(map[string]*AggregatedResult)
+	files := make(map[*AggregatedResult]map[string]bool)
+
+	for _, result := range sortByLocation(results) {
+		location := Location{File: result.File, LineNumber: result.LineNumber, Position: result.Position, Context: result.Context}
+		key := p.findingKey(result)
+		item, ok := index[key]
+		if !ok {
+			item = &AggregatedResult{
+				Group:       result.Group,
+				RuleID:      result.RuleID,
+				RuleName:    result.RuleName,
+				Match:       p.normalizeMatch(result.Match),
+				Analysis:    result.Analysis,
+				DecodeChain: result.DecodeChain,
+				Encoded:     result.Encoded,
+				Sensitive:   result.Sensitive,
+				Severity:    result.Severity,
+				Confidence:  result.Confidence,
+				Category:    result.Category,
+				Tags:        result.Tags,
+				Description: result.Description,
+				Remediation: result.Remediation,
+				References:  result.References,
+				Entropy:     result.Entropy,
+				First:       location,
+			}
+			index[key] = item
+			files[item] = make(map[string]bool)
+			aggregated = append(aggregated, item)
+		}
+		item.Count++
+		item.Last = location
+		if !files[item][result.File] {
+			files[item][result.File] = true
+			item.Files = append(item.Files, result.File)
+		}
+	}
+
+	output := make([]AggregatedResult, 0, len(aggregated))
+	for _, item := range aggregated {
+		item.FileCount = len(item.Files)
+		output = append(output, *item)
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].Count > output[j].Count
+	})
+
+	logging.Infof("aggregation: %d results -> %d unique findings", len(results), len(output))
+	return output
+}
+
+// writeAggregatedJSON 写入聚合结果JSON文件
+func (p *Output) writeAggregatedJSON(filename string, results []AggregatedResult) error {
+	if err := utils.SaveJSON(filename, results); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	return nil
+}
+
+// writeAggregatedCSV 写入聚合结果CSV文件, 多个文件以分号连接
+func (p *Output) writeAggregatedCSV(filename string, results []AggregatedResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	var records [][]string
+	for _, result := range results {
+		records = append(records, []string{
+			result.Group,
+			result.RuleID,
+			result.RuleName,
+			result.Match,
+			result.Analysis,
+			result.DecodeChain,
+			result.Encoded,
+			strconv.FormatBool(result.Sensitive),
+			result.Severity,
+			result.Confidence,
+			result.Category,
+			strings.Join(result.Tags, ";"),
+			fmt.Sprintf("%.2f", result.Entropy),
+			strconv.Itoa(result.Count),
+			strconv.Itoa(result.FileCount),
+			strings.Join(result.Files, ";"),
+			result.First.File,
+			strconv.Itoa(result.First.LineNumber),
+			strconv.Itoa(result.First.Position),
+			result.Last.File,
+			strconv.Itoa(result.Last.LineNumber),
+			strconv.Itoa(result.Last.Position),
+		})
+	}
+
+	if err := writeCSV(filename, aggregateHeaders, records); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return nil
+}
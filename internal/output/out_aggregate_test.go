@@ -0,0 +1,88 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"privacycheck/internal/scanner"
+)
+
+// aggregateSamples 同一密钥在多个文件中重复出现的结果(顺序与扫描结果一样不固定)
+func aggregateSamples() []scanner.ScanResult {
+	return []scanner.ScanResult{
+		{File: "b.js", Group: "Keys", RuleName: "Token", Match: `"sk_live_123"`, Position: 40, LineNumber: 3},
+		{File: "a.js", Group: "Keys", RuleName: "Token", Match: "sk_live_123", Position: 90, LineNumber: 7},
+		{File: "a.js", Group: "Keys", RuleName: "Token", Match: " 'sk_live_123' ", Position: 10, LineNumber: 1},
+		{File: "a.js", Group: "Keys", RuleName: "Other", Match: "sk_live_123", Position: 10, LineNumber: 1},
+		{File: "a.js", Group: "Keys", RuleName: "Token", Match: "sk_live_456", Position: 50, LineNumber: 4},
+	}
+}
+
+// TestDedupeByFile 测试同一文件中重复结果只保留第一次出现
+func TestDedupeByFile(t *testing.T) {
+	output := &Output{}
+	deduped := output.dedupeByFile(aggregateSamples())
+	if len(deduped) != 4 {
+		t.Fatalf("expected 4 results after per-file dedupe, got %d: %+v", len(deduped), deduped)
+	}
+	for _, result := range deduped {
+		if result.File == "a.js" && result.RuleName == "Token" && strings.Contains(result.Match, "123") && result.Position != 10 {
+			t.Errorf("expected first occurrence to be kept, got position %d", result.Position)
+		}
+	}
+}
+
+// TestAggregateResults 测试按规则与规范化匹配值聚合
+func TestAggregateResults(t *testing.T) {
+	output := &Output{}
+	aggregated := output.aggregateResults(aggregateSamples())
+	if len(aggregated) != 3 {
+		t.Fatalf("expected 3 unique findings, got %d: %+v", len(aggregated), aggregated)
+	}
+
+	top := aggregated[0]
+	if top.RuleName != "Token" || top.Match != "sk_live_123" || top.Count != 3 || top.FileCount != 2 {
+		t.Fatalf("unexpected top finding: %+v", top)
+	}
+	if strings.Join(top.Files, ",") != "a.js,b.js" {
+		t.Errorf("expected sorted files, got %v", top.Files)
+	}
+	if top.First.File != "a.js" || top.First.Position != 10 || top.Last.File != "b.js" || top.Last.LineNumber != 3 {
+		t.Errorf("unexpected first/last location: %+v %+v", top.First, top.Last)
+	}
+
+	// 解码后的结果与直接匹配的结果分别聚合, 并保留解码链与编码内容
+	decoded := append(aggregateSamples(), scanner.ScanResult{File: "c.js", Group: "Keys", RuleName: "Token", Match: "sk_live_123", DecodeChain: "base64", Encoded: "c2tfbGl2ZV8xMjM="})
+	aggregated = output.aggregateResults(decoded)
+	if len(aggregated) != 4 || aggregated[0].Count != 3 || aggregated[3].DecodeChain != "base64" || aggregated[3].Encoded != "c2tfbGl2ZV8xMjM=" {
+		t.Errorf("expected decoded finding aggregated separately, got %+v", aggregated)
+	}
+
+	// 先按文件去重后聚合, 次数为出现该值的文件内去重后的次数
+	if deduped := output.aggregateResults(output.dedupeByFile(aggregateSamples())); deduped[0].Count != 2 {
+		t.Errorf("expected count 2 after per-file dedupe, got %d", deduped[0].Count)
+	}
+}
+
+// TestProcessResultsAggregate 测试聚合结果的CSV输出
+func TestProcessResultsAggregate(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "report.csv")
+	output := &Output{OutputFile: outputFile, OutputFormat: "csv", Aggregate: true}
+	if err := output.ProcessResults(aggregateSamples()); err != nil {
+		t.Fatalf("ProcessResults failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "group,rule_id,rule_name,match") {
+		t.Fatalf("unexpected aggregated CSV:\n%s", data)
+	}
+	if !strings.Contains(lines[1], ",3,2,a.js;b.js,a.js,1,10,b.js,3,40") {
+		t.Errorf("unexpected top row: %s", lines[1])
+	}
+}
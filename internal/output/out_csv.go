@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"privacycheck/internal/scanner"
 	"reflect"
+	"strings"
 )
 
 // writeCSV 写入CSV文件
@@ -52,7 +53,7 @@ func (p *Output) getDefaultHeaders(result scanner.ScanResult) []string {
 
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
-		if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
+		if jsonTag, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonTag != "" && jsonTag != "-" {
 			headers = append(headers, jsonTag)
 		}
 	}
@@ -78,6 +79,8 @@ func (p *Output) getFieldValue(result scanner.ScanResult, fieldName string) stri
 		return result.File
 	case "group":
 		return result.Group
+	case "rule_id":
+		return result.RuleID
 	case "rule_name":
 		return result.RuleName
 	case "match":
@@ -90,6 +93,32 @@ func (p *Output) getFieldValue(result scanner.ScanResult, fieldName string) stri
 		return fmt.Sprintf("%d", result.LineNumber)
 	case "sensitive":
 		return fmt.Sprintf("%t", result.Sensitive)
+	case "severity":
+		return result.Severity
+	case "confidence":
+		return result.Confidence
+	case "category":
+		return result.Category
+	case "tags":
+		return strings.Join(result.Tags, ";")
+	case "description":
+		return result.Description
+	case "remediation":
+		return result.Remediation
+	case "references":
+		return strings.Join(result.References, ";")
+	case "entropy":
+		return fmt.Sprintf("%.2f", result.Entropy)
+	case "matched_rules":
+		return strings.Join(result.MatchedRules, ";")
+	case "decode_chain":
+		return result.DecodeChain
+	case "encoded":
+		return result.Encoded
+	case "encoded_size":
+		return fmt.Sprintf("%d", result.EncodedSize)
+	case "analysis":
+		return result.Analysis
 	default:
 		return ""
 	}
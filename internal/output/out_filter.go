@@ -36,6 +36,8 @@ func (p *Output) groupByField(results []scanner.ScanResult, field string) map[st
 		switch field {
 		case "group":
 			key = result.Group
+		case "rule_id":
+			key = result.RuleID
 		case "rule_name":
 			key = result.RuleName
 		case "file":
@@ -82,6 +84,9 @@ func (p *Output) filterSingleResult(result scanner.ScanResult, keyMap map[string
 	if keyMap["group"] {
 		newResult.Group = result.Group
 	}
+	if keyMap["rule_id"] {
+		newResult.RuleID = result.RuleID
+	}
 	if keyMap["rule_name"] {
 		newResult.RuleName = result.RuleName
 	}
@@ -100,6 +105,45 @@ func (p *Output) filterSingleResult(result scanner.ScanResult, keyMap map[string
 	if keyMap["sensitive"] {
 		newResult.Sensitive = result.Sensitive
 	}
+	if keyMap["severity"] {
+		newResult.Severity = result.Severity
+	}
+	if keyMap["confidence"] {
+		newResult.Confidence = result.Confidence
+	}
+	if keyMap["category"] {
+		newResult.Category = result.Category
+	}
+	if keyMap["tags"] {
+		newResult.Tags = result.Tags
+	}
+	if keyMap["description"] {
+		newResult.Description = result.Description
+	}
+	if keyMap["remediation"] {
+		newResult.Remediation = result.Remediation
+	}
+	if keyMap["references"] {
+		newResult.References = result.References
+	}
+	if keyMap["entropy"] {
+		newResult.Entropy = result.Entropy
+	}
+	if keyMap["matched_rules"] {
+		newResult.MatchedRules = result.MatchedRules
+	}
+	if keyMap["decode_chain"] {
+		newResult.DecodeChain = result.DecodeChain
+	}
+	if keyMap["encoded"] {
+		newResult.Encoded = result.Encoded
+	}
+	if keyMap["encoded_size"] {
+		newResult.EncodedSize = result.EncodedSize
+	}
+	if keyMap["analysis"] {
+		newResult.Analysis = result.Analysis
+	}
 
 	return newResult
 }
@@ -0,0 +1,235 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/scanner"
+)
+
+// 过滤器动作
+const (
+	FilterActionExclude = "exclude" // 命中时丢弃结果
+	FilterActionInclude = "include" // 仅保留命中的结果
+)
+
+// 条件组合逻辑
+const (
+	FilterLogicAnd = "and"
+	FilterLogicOr  = "or"
+)
+
+// filterFields 支持过滤的结果字段
+var filterFields = map[string]bool{
+	"file":      true,
+	"group":     true,
+	"rule_id":   true,
+	"rule_name": true,
+	"match":     true,
+	"context":   true,
+	"severity":  true,
+	"category":  true,
+	"tags":      true,
+}
+
+// FilterCondition 单个字段的正则条件
+type FilterCondition struct {
+	Field  string `yaml:"field" json:"field"`   // 结果字段(file/group/rule_id/rule_name/match/context/severity/category/tags)
+	Regex  string `yaml:"regex" json:"regex"`   // 正则表达式
+	Negate bool   `yaml:"negate" json:"negate"` // 取反, 字段不匹配正则时条件成立
+
+	compiled *regexp.Regexp
+}
+
+// ResultFilter 由多个条件组合而成的过滤器
+type ResultFilter struct {
+	Name       string            `yaml:"name" json:"name"`             // 过滤器名称, 仅用于日志
+	Action     string            `yaml:"action" json:"action"`         // exclude(默认) 或 include
+	Logic      string            `yaml:"logic" json:"logic"`           // and(默认) 或 or
+	Conditions []FilterCondition `yaml:"conditions" json:"conditions"` // 条件列表
+}
+
+// ResultFilterConfig 过滤器配置文件
+type ResultFilterConfig struct {
+	Filters []ResultFilter `yaml:"filters" json:"filters"`
+}
+
+// LoadResultFilters 从YAML文件加载结果过滤器
+func LoadResultFilters(filterFile string) ([]ResultFilter, error) {
+	var config ResultFilterConfig
+	if err := utils.LoadYAML(filterFile, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse the filter file:%s error: %w", filterFile, err)
+	}
+	return config.Filters, nil
+}
+
+// 命令行过滤表达式的条件分隔符, 两侧必须有空白, 正则中不带空白的 && 与 || (如 a||b) 不会被拆分
+var (
+	filterAndSeparator = regexp.MustCompile(`\s+&&\s+`)
+	filterOrSeparator  = regexp.MustCompile(`\s+\|\|\s+`)
+)
+
+// ParseFilterExpr 解析命令行过滤表达式
+// 格式: field:regex, 多个条件使用两侧带空白的 && (与) 或 || (或) 连接, 只支持单层的与/或, 不支持混用与括号
+// 字段名前加 ! 表示取反, 例如: file:_test\.go$ && context:mock
+// 正则本身包含两侧带空白的 && 或 || 时需要写入过滤器配置文件
+func ParseFilterExpr(expr, action string) (ResultFilter, error) {
+	filter := ResultFilter{Name: expr, Action: action, Logic: FilterLogicAnd}
+
+	parts := []string{expr}
+	hasAnd := filterAndSeparator.MatchString(expr)
+	hasOr := filterOrSeparator.MatchString(expr)
+	switch {
+	case hasAnd && hasOr:
+		return filter, fmt.Errorf("filter expression [%s] mixes && and ||", expr)
+	case hasAnd:
+		parts = filterAndSeparator.Split(expr, -1)
+	case hasOr:
+		parts = filterOrSeparator.Split(expr, -1)
+		filter.Logic = FilterLogicOr
+	}
+
+	for _, part := range parts {
+		field, regex, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			return filter, fmt.Errorf("filter condition [%s] must be in field:regex format", part)
+		}
+		condition := FilterCondition{Field: strings.TrimSpace(field), Regex: regex}
+		if strings.HasPrefix(condition.Field, "!") {
+			condition.Negate = true
+			condition.Field = strings.TrimPrefix(condition.Field, "!")
+		}
+		filter.Conditions = append(filter.Conditions, condition)
+	}
+
+	return filter, nil
+}
+
+// compile 校验并预编译过滤器
+func (f *ResultFilter) compile() error {
+	f.Action = strings.ToLower(strings.TrimSpace(f.Action))
+	if f.Action == "" {
+		f.Action = FilterActionExclude
+	}
+	if f.Action != FilterActionExclude && f.Action != FilterActionInclude {
+		return fmt.Errorf("filter [%s] has invalid action: %s", f.Name, f.Action)
+	}
+
+	f.Logic = strings.ToLower(strings.TrimSpace(f.Logic))
+	if f.Logic == "" {
+		f.Logic = FilterLogicAnd
+	}
+	if f.Logic != FilterLogicAnd && f.Logic != FilterLogicOr {
+		return fmt.Errorf("filter [%s] has invalid logic: %s", f.Name, f.Logic)
+	}
+
+	if len(f.Conditions) == 0 {
+		return fmt.Errorf("filter [%s] has no conditions", f.Name)
+	}
+
+	for i := range f.Conditions {
+		condition := &f.Conditions[i]
+		if !filterFields[condition.Field] {
+			return fmt.Errorf("filter [%s] has invalid field: %s", f.Name, condition.Field)
+		}
+		compiled, err := regexp.Compile(condition.Regex)
+		if err != nil {
+			return fmt.Errorf("filter [%s] field %s regex compile error: %w", f.Name, condition.Field, err)
+		}
+		condition.compiled = compiled
+	}
+
+	return nil
+}
+
+// matches 判断结果是否命中过滤器
+func (f *ResultFilter) matches(p *Output, result scanner.ScanResult) bool {
+	for _, condition := range f.Conditions {
+		matched := condition.compiled.MatchString(p.getFieldValue(result, condition.Field)) != condition.Negate
+		if f.Logic == FilterLogicOr && matched {
+			return true
+		}
+		if f.Logic == FilterLogicAnd && !matched {
+			return false
+		}
+	}
+	return f.Logic == FilterLogicAnd
+}
+
+// LoadFilters 加载并编译过滤文件与命令行中的过滤表达式
+func (p *Output) LoadFilters() error {
+	var filters []ResultFilter
+
+	if p.FilterFile != "" {
+		fileFilters, err := LoadResultFilters(p.FilterFile)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, fileFilters...)
+	}
+
+	for _, expr := range p.FilterInclude {
+		filter, err := ParseFilterExpr(expr, FilterActionInclude)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, filter)
+	}
+
+	for _, expr := range p.FilterExclude {
+		filter, err := ParseFilterExpr(expr, FilterActionExclude)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, filter)
+	}
+
+	for i := range filters {
+		if err := filters[i].compile(); err != nil {
+			return err
+		}
+	}
+
+	p.resultFilters = filters
+	return nil
+}
+
+// filterByRegex 按正则过滤器筛选结果
+// 存在 include 过滤器时, 结果至少需要命中其中一个; 命中任意 exclude 过滤器的结果被丢弃
+func (p *Output) filterByRegex(results []scanner.ScanResult) []scanner.ScanResult {
+	var includes, excludes []ResultFilter
+	for _, filter := range p.resultFilters {
+		if filter.Action == FilterActionInclude {
+			includes = append(includes, filter)
+		} else {
+			excludes = append(excludes, filter)
+		}
+	}
+
+	var filtered []scanner.ScanResult
+	for _, result := range results {
+		if len(includes) > 0 && !p.matchAnyFilter(includes, result) {
+			continue
+		}
+		if p.matchAnyFilter(excludes, result) {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+
+	logging.Infof("regex filtering: %d -> %d", len(results), len(filtered))
+	return filtered
+}
+
+// matchAnyFilter 判断结果是否命中任意过滤器
+func (p *Output) matchAnyFilter(filters []ResultFilter, result scanner.ScanResult) bool {
+	for i := range filters {
+		if filters[i].matches(p, result) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"testing"
+
+	"privacycheck/internal/scanner"
+)
+
+// TestParseFilterExpr 测试命令行过滤表达式解析
+func TestParseFilterExpr(t *testing.T) {
+	filter, err := ParseFilterExpr(`file:_test\.go$ && !context:mock`, FilterActionExclude)
+	if err != nil {
+		t.Fatalf("ParseFilterExpr failed: %v", err)
+	}
+	if filter.Logic != FilterLogicAnd || len(filter.Conditions) != 2 {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+	if filter.Conditions[1].Field != "context" || !filter.Conditions[1].Negate {
+		t.Errorf("expected negated context condition, got %+v", filter.Conditions[1])
+	}
+
+	if _, err := ParseFilterExpr("file:a && match:b || context:c", FilterActionExclude); err == nil {
+		t.Errorf("expected error for mixed logic")
+	}
+	if _, err := ParseFilterExpr("no-field-separator", FilterActionExclude); err == nil {
+		t.Errorf("expected error for missing field")
+	}
+
+	// 正则中不带空白的 || 与 && 属于正则本身
+	filter, err = ParseFilterExpr(`match:a||b && rule_name:x&&y|z`, FilterActionInclude)
+	if err != nil {
+		t.Fatalf("ParseFilterExpr failed: %v", err)
+	}
+	if filter.Logic != FilterLogicAnd || len(filter.Conditions) != 2 {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+	if filter.Conditions[0].Regex != "a||b" || filter.Conditions[1].Regex != "x&&y|z" {
+		t.Errorf("expected regexes to be kept whole, got %+v", filter.Conditions)
+	}
+
+	filter, err = ParseFilterExpr(`match:token|secret ||	!file:(vendor|node_modules)/`, FilterActionExclude)
+	if err != nil {
+		t.Fatalf("ParseFilterExpr failed: %v", err)
+	}
+	if filter.Logic != FilterLogicOr || len(filter.Conditions) != 2 || filter.Conditions[0].Regex != "token|secret" {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+}
+
+// TestFilterByRegex 测试正则过滤器的组合效果
+func TestFilterByRegex(t *testing.T) {
+	results := []scanner.ScanResult{
+		{File: "pkg/user_test.go", RuleName: "Password Field", Match: "password=123456", Context: "mockUser password=123456"},
+		{File: "pkg/user_test.go", RuleName: "Password Field", Match: "password=abcdef", Context: "realUser password=abcdef"},
+		{File: "pkg/user.go", RuleName: "Password Field", Match: "password=654321", Context: "mockUser password=654321"},
+		{File: "pkg/user.go", RuleName: "Email", Match: "admin@example.com", Context: "admin@example.com"},
+	}
+
+	p := &Output{
+		FilterExclude: []string{`file:_test\.go$ && context:mock`},
+		FilterInclude: []string{`rule_name:(?i)password`},
+	}
+	if err := p.LoadFilters(); err != nil {
+		t.Fatalf("LoadFilters failed: %v", err)
+	}
+
+	filtered := p.filterByRegex(results)
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(filtered), filtered)
+	}
+	if filtered[0].Match != "password=abcdef" || filtered[1].Match != "password=654321" {
+		t.Errorf("unexpected results: %+v", filtered)
+	}
+}
+
+// TestLoadFiltersInvalid 测试非法过滤器配置
+func TestLoadFiltersInvalid(t *testing.T) {
+	invalid := [][]string{
+		{"position:1"},
+		{"match:(unclosed"},
+	}
+	for _, exprs := range invalid {
+		p := &Output{FilterExclude: exprs}
+		if err := p.LoadFilters(); err == nil {
+			t.Errorf("expected error for %v", exprs)
+		}
+	}
+}
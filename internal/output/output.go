@@ -17,7 +17,14 @@ type Output struct {
 	OutputFormat  string
 	FormatResults bool
 	BlockMatches  []string
+	FilterFile    string   // 正则过滤器配置文件
+	FilterInclude []string // 保留过滤表达式
+	FilterExclude []string // 排除过滤表达式
+	DedupeFile    bool     // 同一文件中相同规则与匹配值的结果只保留一次
+	Aggregate     bool     // 按规则与规范化匹配值聚合输出
 	ProjectName   string
+
+	resultFilters []ResultFilter
 }
 
 // ProcessResults 处理扫描结果
@@ -40,6 +47,16 @@ func (p *Output) ProcessResults(results []scanner.ScanResult) error {
 		results = p.filterBlockMatches(results)
 	}
 
+	// 正则表达式过滤
+	if len(p.resultFilters) > 0 {
+		results = p.filterByRegex(results)
+	}
+
+	// 同一文件中的重复结果只保留第一次出现
+	if p.DedupeFile {
+		results = p.dedupeByFile(results)
+	}
+
 	// 按组分组输出
 	var groupedResults map[string][]scanner.ScanResult
 	if p.OutputGroup {
@@ -48,6 +65,19 @@ func (p *Output) ProcessResults(results []scanner.ScanResult) error {
 		groupedResults = map[string][]scanner.ScanResult{"": results}
 	}
 
+	// 聚合输出
+	if p.Aggregate {
+		if len(p.OutputKeys) > 0 {
+			logging.Warnf("output keys are ignored when aggregating results")
+		}
+		for groupName, groupResults := range groupedResults {
+			if err := p.outputAggregated(groupName, p.aggregateResults(groupResults)); err != nil {
+				return fmt.Errorf("输出结果失败: %w", err)
+			}
+		}
+		return nil
+	}
+
 	// 过滤输出字段
 	if len(p.OutputKeys) > 0 {
 		for groupName, groupResults := range groupedResults {
@@ -67,6 +97,55 @@ func (p *Output) ProcessResults(results []scanner.ScanResult) error {
 
 // outputGroup 输出单个组的结果
 func (p *Output) outputGroup(groupName string, results []scanner.ScanResult) error {
+	outputFile, err := p.outputFileName(groupName)
+	if err != nil {
+		return err
+	}
+
+	// 根据格式输出
+	switch p.OutputFormat {
+	case "csv":
+		if err := p.writeCSV(outputFile, results); err != nil {
+			return err
+		}
+	default:
+		if err := p.writeJSON(outputFile, results); err != nil {
+			return err
+		}
+	}
+
+	logging.Infof("analysis results [group:%s|format:%s] saved to: %s",
+		groupName, p.OutputFormat, outputFile)
+
+	return nil
+}
+
+// outputAggregated 输出单个组的聚合结果
+func (p *Output) outputAggregated(groupName string, results []AggregatedResult) error {
+	outputFile, err := p.outputFileName(groupName)
+	if err != nil {
+		return err
+	}
+
+	switch p.OutputFormat {
+	case "csv":
+		if err := p.writeAggregatedCSV(outputFile, results); err != nil {
+			return err
+		}
+	default:
+		if err := p.writeAggregatedJSON(outputFile, results); err != nil {
+			return err
+		}
+	}
+
+	logging.Infof("aggregated results [group:%s|format:%s] saved to: %s",
+		groupName, p.OutputFormat, outputFile)
+
+	return nil
+}
+
+// outputFileName 生成单个组的输出文件名并确保输出目录存在
+func (p *Output) outputFileName(groupName string) (string, error) {
 	// 生成输出文件名
 	baseOutput := p.OutputFile
 	if baseOutput == "" {
@@ -87,23 +166,7 @@ func (p *Output) outputGroup(groupName string, results []scanner.ScanResult) err
 
 	// 确保输出目录存在
 	if err := utils.EnsureDir(outputFile, true); err != nil {
-		return fmt.Errorf("创建输出目录失败: %w", err)
-	}
-
-	// 根据格式输出
-	switch p.OutputFormat {
-	case "csv":
-		if err := p.writeCSV(outputFile, results); err != nil {
-			return err
-		}
-	default:
-		if err := p.writeJSON(outputFile, results); err != nil {
-			return err
-		}
+		return "", fmt.Errorf("创建输出目录失败: %w", err)
 	}
-
-	logging.Infof("analysis results [group:%s|format:%s] saved to: %s",
-		groupName, p.OutputFormat, outputFile)
-
-	return nil
+	return outputFile, nil
 }
@@ -2,6 +2,7 @@ package output
 
 import (
 	"github.com/winezer0/xutils/logging"
+	"privacycheck/internal/baserule"
 	"privacycheck/internal/scanner"
 )
 
@@ -20,23 +21,38 @@ func (p *Output) printStatistics(results []scanner.ScanResult) {
 // statisticsData 统计数据结构
 type statisticsData struct {
 	sensitiveCount int
+	uniqueCount    map[string]bool // 按(规则, 规范化匹配值)去重后的结果
+	uniqueInFile   map[string]bool // 按(文件, 规则, 规范化匹配值)去重后的结果
 	fileCount      map[string]bool
 	groupCount     map[string]int
 	ruleCount      map[string]int
+	severityCount  map[string]int
+	categoryCount  map[string]int
+	tagCount       map[string]int
 }
 
 // calculateStatistics 计算统计信息
 func (p *Output) calculateStatistics(results []scanner.ScanResult) *statisticsData {
 	stats := &statisticsData{
-		fileCount:  make(map[string]bool),
-		groupCount: make(map[string]int),
-		ruleCount:  make(map[string]int),
+		uniqueCount:   make(map[string]bool),
+		uniqueInFile:  make(map[string]bool),
+		fileCount:     make(map[string]bool),
+		groupCount:    make(map[string]int),
+		ruleCount:     make(map[string]int),
+		severityCount: make(map[string]int),
+		categoryCount: make(map[string]int),
+		tagCount:      make(map[string]int),
 	}
 
 	for _, result := range results {
 		// 文件统计
 		stats.fileCount[result.File] = true
 
+		// 去重统计
+		key := p.findingKey(result)
+		stats.uniqueCount[key] = true
+		stats.uniqueInFile[result.File+"\x00"+key] = true
+
 		// 敏感信息统计
 		if result.Sensitive {
 			stats.sensitiveCount++
@@ -47,6 +63,17 @@ func (p *Output) calculateStatistics(results []scanner.ScanResult) *statisticsDa
 
 		// 规则统计
 		stats.ruleCount[result.RuleName]++
+
+		// 严重等级统计
+		stats.severityCount[result.Severity]++
+
+		// 分类与标签统计
+		if result.Category != "" {
+			stats.categoryCount[result.Category]++
+		}
+		for _, tag := range result.Tags {
+			stats.tagCount[tag]++
+		}
 	}
 
 	return stats
@@ -56,13 +83,21 @@ func (p *Output) calculateStatistics(results []scanner.ScanResult) *statisticsDa
 func (p *Output) displayStatistics(stats *statisticsData, results []scanner.ScanResult) {
 	logging.Info("=== Scan Results Statistics ===")
 	logging.Infof("total results: %d", len(results))
+	logging.Infof("unique findings: %d (by rule and match), %d (by file, rule and match)", len(stats.uniqueCount), len(stats.uniqueInFile))
 	logging.Infof("sensitive information: %d", stats.sensitiveCount)
 	logging.Infof("files involved: %d", len(stats.fileCount))
 	logging.Infof("rule groups: %d", len(stats.groupCount))
 
+	// 按严重等级统计
+	p.displaySeverityStatistics(stats.severityCount)
+
 	// 按规则组统计
 	p.displayGroupStatistics(stats.groupCount)
 
+	// 按分类和标签统计
+	p.displayCountStatistics("statistics by category:", stats.categoryCount)
+	p.displayCountStatistics("statistics by tag:", stats.tagCount)
+
 	// 显示最常触发的规则
 	p.displayTopRules(stats.ruleCount)
 
@@ -77,6 +112,28 @@ func (p *Output) displayGroupStatistics(groupCount map[string]int) {
 	}
 }
 
+// displaySeverityStatistics 按严重等级由高到低显示统计
+func (p *Output) displaySeverityStatistics(severityCount map[string]int) {
+	logging.Info("statistics by severity:")
+	for i := len(baserule.SeverityLevels) - 1; i >= 0; i-- {
+		severity := baserule.SeverityLevels[i]
+		if count := severityCount[severity]; count > 0 {
+			logging.Infof("  %s: %d", severity, count)
+		}
+	}
+}
+
+// displayCountStatistics 按计数由高到低显示统计, 无数据时不输出
+func (p *Output) displayCountStatistics(title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	logging.Info(title)
+	for _, item := range p.sortRulesByCount(counts) {
+		logging.Infof("  %s: %d", item.name, item.count)
+	}
+}
+
 // displayTopRules 显示最常触发的规则
 func (p *Output) displayTopRules(ruleCount map[string]int) {
 	logging.Info("most frequently triggered rules:")
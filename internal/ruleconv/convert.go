@@ -0,0 +1,176 @@
+package ruleconv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"privacycheck/internal/baserule"
+)
+
+// 支持的规则格式
+const (
+	FormatPrivacyCheck = "privacycheck" // PrivacyCheck YAML 规则
+	FormatHAE          = "hae"          // HAE YAML 规则
+	FormatGitleaks     = "gitleaks"     // gitleaks TOML 规则
+)
+
+// Formats 返回支持的规则格式
+func Formats() []string {
+	return []string{FormatPrivacyCheck, FormatHAE, FormatGitleaks}
+}
+
+// Issue 转换过程中无法表示或被丢弃的内容
+type Issue struct {
+	Rule    string // 规则标识(组名: 规则名 或 gitleaks 规则ID), 为空表示全局
+	Message string // 问题描述
+}
+
+// String 返回问题描述
+func (i Issue) String() string {
+	if i.Rule == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("[%s] %s", i.Rule, i.Message)
+}
+
+// Load 按指定格式加载规则文件, 转换为 RuleConfig
+// group 为 gitleaks 规则导入后的规则组名称
+func Load(format string, paths []string, group string) (*baserule.RuleConfig, []Issue, error) {
+	switch format {
+	case FormatPrivacyCheck, FormatHAE:
+		config, conflicts, err := baserule.LoadRules(paths)
+		if err != nil {
+			return nil, nil, err
+		}
+		var issues []Issue
+		for _, conflict := range conflicts {
+			issues = append(issues, Issue{Message: conflict.String()})
+		}
+		return config, issues, nil
+	case FormatGitleaks:
+		config := &baserule.RuleConfig{}
+		var issues []Issue
+		for _, path := range paths {
+			rules, fileIssues, err := LoadGitleaks(path, group)
+			if err != nil {
+				return nil, nil, err
+			}
+			config.Rules = append(config.Rules, rules)
+			issues = append(issues, fileIssues...)
+		}
+		return config, issues, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported source format: %s", format)
+	}
+}
+
+// Export 将 RuleConfig 导出为指定格式
+func Export(config *baserule.RuleConfig, format string) ([]byte, []Issue, error) {
+	switch format {
+	case FormatPrivacyCheck:
+		data, err := marshalYAML(config, true)
+		return data, nil, err
+	case FormatHAE:
+		return ExportHAE(config)
+	case FormatGitleaks:
+		return ExportGitleaks(config)
+	default:
+		return nil, nil, fmt.Errorf("unsupported target format: %s", format)
+	}
+}
+
+// ruleIdentifier 返回用于问题描述的规则标识
+func ruleIdentifier(group string, rule baserule.Rule) string {
+	return fmt.Sprintf("%s: %s", group, rule.Name)
+}
+
+// droppedFields 返回目标格式无法表示的非空规则字段(按规则字段顺序, 使用yaml键名)
+func droppedFields(rule baserule.Rule, kept map[string]bool) []string {
+	var dropped []string
+	value := reflect.ValueOf(rule)
+	for i := 0; i < value.NumField(); i++ {
+		key, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if kept[key] || value.Field(i).IsZero() {
+			continue
+		}
+		dropped = append(dropped, key)
+	}
+	return dropped
+}
+
+// slugPattern 规则ID中不允许出现的字符
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify 将规则名称转换为规则ID
+func slugify(name string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// marshalYAML 序列化为YAML, compact 为 true 时省略值为空的规则字段以保持输出简洁
+func marshalYAML(value interface{}, compact bool) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	if compact {
+		pruneEmpty(&node)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// keptKeys 即使为空也保留的键
+var keptKeys = map[string]bool{"name": true, "f_regex": true, "loaded": true, "rule": true, "rules": true}
+
+// pruneEmpty 递归删除映射中值为空字符串、0、false、空列表或空映射的键
+func pruneEmpty(node *yaml.Node) {
+	for _, child := range node.Content {
+		pruneEmpty(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	var content []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !keptKeys[key.Value] && isEmptyNode(value) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	node.Content = content
+}
+
+// isEmptyNode 判断节点是否为空值
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!str":
+			return node.Value == ""
+		case "!!int", "!!float":
+			return node.Value == "0"
+		case "!!bool":
+			return node.Value == "false"
+		case "!!null":
+			return true
+		}
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
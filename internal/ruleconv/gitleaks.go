@@ -0,0 +1,268 @@
+package ruleconv
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"privacycheck/internal/baserule"
+)
+
+// gitleaksConfig gitleaks 配置文件
+type gitleaksConfig struct {
+	Title      string              `toml:"title,omitempty"`
+	Extend     *gitleaksExtend     `toml:"extend,omitempty"`
+	Allowlist  *gitleaksAllowlist  `toml:"allowlist,omitempty"`
+	Allowlists []gitleaksAllowlist `toml:"allowlists,omitempty"`
+	Rules      []gitleaksRule      `toml:"rules"`
+}
+
+// gitleaksExtend 继承其他 gitleaks 配置
+type gitleaksExtend struct {
+	Path          string   `toml:"path,omitempty"`
+	URL           string   `toml:"url,omitempty"`
+	UseDefault    bool     `toml:"useDefault,omitempty"`
+	DisabledRules []string `toml:"disabledRules,omitempty"`
+}
+
+// gitleaksRule gitleaks 规则
+type gitleaksRule struct {
+	ID          string                   `toml:"id"`
+	Description string                   `toml:"description,omitempty"`
+	Regex       string                   `toml:"regex,omitempty"`
+	SecretGroup int                      `toml:"secretGroup,omitzero"`
+	Entropy     float64                  `toml:"entropy,omitzero"`
+	Keywords    []string                 `toml:"keywords,omitempty"`
+	Path        string                   `toml:"path,omitempty"`
+	Tags        []string                 `toml:"tags,omitempty"`
+	SkipReport  bool                     `toml:"skipReport,omitempty"`
+	Required    []map[string]interface{} `toml:"required,omitempty"`
+	Allowlist   *gitleaksAllowlist       `toml:"allowlist,omitempty"`
+	Allowlists  []gitleaksAllowlist      `toml:"allowlists,omitempty"`
+}
+
+// gitleaksAllowlist gitleaks 白名单
+type gitleaksAllowlist struct {
+	Description string   `toml:"description,omitempty"`
+	Condition   string   `toml:"condition,omitempty"`
+	RegexTarget string   `toml:"regexTarget,omitempty"`
+	Commits     []string `toml:"commits,omitempty"`
+	Paths       []string `toml:"paths,omitempty"`
+	Regexes     []string `toml:"regexes,omitempty"`
+	StopWords   []string `toml:"stopwords,omitempty"`
+}
+
+// gitleaksKeptFields 导出为 gitleaks 时可以表示的规则字段
+var gitleaksKeptFields = map[string]bool{
+	"id": true, "name": true, "f_regex": true, "loaded": true, "engine": true, "sensitive": true,
+	"tags": true, "secret_group": true, "entropy": true, "keywords": true, "allowlist": true,
+}
+
+// LoadGitleaks 加载 gitleaks TOML 规则文件, 转换为一个规则组
+// gitleaks 规则均视为凭证类敏感信息, 使用 go 引擎匹配; 无法表示的字段会作为问题返回
+func LoadGitleaks(path, group string) (baserule.Rules, []Issue, error) {
+	rules := baserule.Rules{Group: group}
+
+	var config gitleaksConfig
+	meta, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return rules, nil, fmt.Errorf("failed to parse the gitleaks file:%s error: %w", path, err)
+	}
+
+	var issues []Issue
+	for _, key := range meta.Undecoded() {
+		issues = append(issues, Issue{Message: fmt.Sprintf("unsupported gitleaks key ignored: %s", key)})
+	}
+	if config.Extend != nil {
+		issues = append(issues, Issue{Message: "extend is not supported, only rules defined in the file are converted"})
+	}
+
+	// 全局白名单作用于所有规则
+	var global baserule.Allowlist
+	for _, allowlist := range collectAllowlists(config.Allowlist, config.Allowlists) {
+		global.Append(importAllowlist(allowlist, "", &issues))
+	}
+
+	for _, gitleaksRule := range config.Rules {
+		rule, ok := importGitleaksRule(gitleaksRule, &issues)
+		if !ok {
+			continue
+		}
+		rule.Allowlist.Append(global)
+		rules.Rule = append(rules.Rule, rule)
+	}
+
+	return rules, issues, nil
+}
+
+// importGitleaksRule 转换单条 gitleaks 规则
+func importGitleaksRule(source gitleaksRule, issues *[]Issue) (baserule.Rule, bool) {
+	report := func(message string) {
+		*issues = append(*issues, Issue{Rule: source.ID, Message: message})
+	}
+
+	if source.Regex == "" {
+		report("path-only rule skipped, rules without regex are not supported")
+		return baserule.Rule{}, false
+	}
+
+	rule := baserule.Rule{
+		ID:          source.ID,
+		Name:        source.Description,
+		FRegex:      source.Regex,
+		Loaded:      true,
+		Engine:      string(baserule.RegexEngineGo),
+		Sensitive:   true,
+		Severity:    "high",
+		Category:    "credentials",
+		Tags:        source.Tags,
+		Description: source.Description,
+		SecretGroup: source.SecretGroup,
+		Entropy:     source.Entropy,
+		Keywords:    source.Keywords,
+	}
+	if rule.Name == "" {
+		rule.Name = source.ID
+	}
+
+	// 未设置 secretGroup 时 gitleaks 提取第一个非空的捕获组, 规则按固定的捕获组提取
+	if compiled, err := regexp.Compile(source.Regex); err == nil {
+		if rule.SecretGroup == 0 && compiled.NumSubexp() > 0 {
+			rule.SecretGroup = 1
+			if compiled.NumSubexp() > 1 {
+				report("secretGroup unset, gitleaks extracts the first non-empty capture group, secret_group set to 1")
+			}
+		}
+	}
+	// 扫描时规则总是忽略大小写匹配
+	if caseSensitive(source.Regex) {
+		report("regex is case-sensitive in gitleaks but matched case-insensitively, it may report more matches")
+	}
+
+	if source.Path != "" {
+		report(fmt.Sprintf("path restriction dropped: %s", source.Path))
+	}
+	if source.SkipReport {
+		report("skipReport dropped, the rule will report its findings")
+	}
+	if len(source.Required) > 0 {
+		report("required (composite rule) dropped, the primary regex is reported on its own")
+	}
+
+	for _, allowlist := range collectAllowlists(source.Allowlist, source.Allowlists) {
+		rule.Allowlist.Append(importAllowlist(allowlist, source.ID, issues))
+	}
+
+	return rule, true
+}
+
+// caseSensitive 判断正则是否区分大小写: 添加 (?i) 后语法树发生变化时正则中存在区分大小写的字母
+func caseSensitive(regex string) bool {
+	original, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	folded, err := syntax.Parse("(?i)"+regex, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return original.Simplify().String() != folded.Simplify().String()
+}
+
+// collectAllowlists 合并旧版单个 allowlist 与新版 allowlists 列表
+func collectAllowlists(single *gitleaksAllowlist, list []gitleaksAllowlist) []gitleaksAllowlist {
+	if single != nil {
+		list = append([]gitleaksAllowlist{*single}, list...)
+	}
+	return list
+}
+
+// importAllowlist 转换 gitleaks 白名单
+func importAllowlist(source gitleaksAllowlist, ruleID string, issues *[]Issue) baserule.Allowlist {
+	report := func(message string) {
+		*issues = append(*issues, Issue{Rule: ruleID, Message: message})
+	}
+
+	if len(source.Commits) > 0 {
+		report("allowlist commits dropped, git history is not scanned")
+	}
+	if strings.EqualFold(source.Condition, "AND") {
+		report("allowlist condition AND is treated as OR")
+	}
+	if len(source.Regexes) > 0 && source.RegexTarget != "" && source.RegexTarget != "secret" {
+		report(fmt.Sprintf("allowlist regexTarget %s is treated as secret", source.RegexTarget))
+	}
+
+	return baserule.Allowlist{
+		Regexes:   source.Regexes,
+		Paths:     source.Paths,
+		Stopwords: source.StopWords,
+	}
+}
+
+// ExportGitleaks 将规则导出为 gitleaks TOML
+// 规则默认忽略大小写匹配, 导出的正则会添加 (?i); 无法使用 Go 正则编译或未启用的规则会被跳过
+func ExportGitleaks(config *baserule.RuleConfig) ([]byte, []Issue, error) {
+	var issues []Issue
+	output := gitleaksConfig{Title: "PrivacyCheck rules"}
+	usedIDs := make(map[string]bool)
+
+	for _, group := range config.Rules {
+		for _, rule := range group.Rule {
+			identifier := ruleIdentifier(group.Group, rule)
+			if !rule.Loaded {
+				issues = append(issues, Issue{Rule: identifier, Message: "disabled rule skipped"})
+				continue
+			}
+
+			regex := rule.FRegex
+			if !strings.HasPrefix(regex, "(?i)") {
+				regex = "(?i)" + regex
+			}
+			if _, err := regexp.Compile(regex); err != nil {
+				issues = append(issues, Issue{Rule: identifier, Message: fmt.Sprintf("rule skipped, regex is not supported by Go regexp: %v", err)})
+				continue
+			}
+
+			id := rule.ID
+			if id == "" {
+				id = slugify(rule.Name)
+			}
+			for base, i := id, 2; usedIDs[id]; i++ {
+				id = fmt.Sprintf("%s-%d", base, i)
+			}
+			usedIDs[id] = true
+
+			exported := gitleaksRule{
+				ID:          id,
+				Description: rule.Name,
+				Regex:       regex,
+				SecretGroup: rule.SecretGroup,
+				Entropy:     rule.Entropy,
+				Keywords:    rule.Keywords,
+				Tags:        rule.Tags,
+			}
+			if !rule.Allowlist.IsEmpty() {
+				exported.Allowlists = []gitleaksAllowlist{{
+					Regexes:   rule.Allowlist.Regexes,
+					Paths:     rule.Allowlist.Paths,
+					StopWords: rule.Allowlist.Stopwords,
+				}}
+			}
+			output.Rules = append(output.Rules, exported)
+
+			if dropped := droppedFields(rule, gitleaksKeptFields); len(dropped) > 0 {
+				issues = append(issues, Issue{Rule: identifier, Message: fmt.Sprintf("fields dropped: %s", strings.Join(dropped, ", "))})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(output); err != nil {
+		return nil, issues, fmt.Errorf("failed to encode gitleaks rules: %w", err)
+	}
+	return buf.Bytes(), issues, nil
+}
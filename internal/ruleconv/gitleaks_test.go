@@ -0,0 +1,155 @@
+package ruleconv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+const sampleGitleaks = `
+title = "sample"
+
+[allowlist]
+paths = ['''(^|/)vendor/''']
+
+[[rules]]
+id = "aws-access-token"
+description = "AWS Access Token"
+regex = '''\b((?:AKIA|ASIA)[A-Z2-7]{16})\b'''
+secretGroup = 1
+entropy = 3
+keywords = ["akia"]
+tags = ["aws"]
+[[rules.allowlists]]
+regexes = ['''.+EXAMPLE$''']
+
+[[rules]]
+id = "generic-api-key"
+description = "Generic API Key"
+regex = '''(?i)api_key\s*=\s*([a-z0-9]{32})'''
+
+[[rules]]
+id = "quoted-token"
+description = "Quoted Token"
+regex = '''(?i)token\s*=\s*(?:"([a-z0-9]{16,})"|'([a-z0-9]{16,})')'''
+
+[[rules]]
+id = "pkcs12-file"
+description = "PKCS12 file"
+path = '''(?i)\.p12$'''
+`
+
+// TestLoadGitleaks 测试导入 gitleaks 规则
+func TestLoadGitleaks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gitleaks.toml")
+	if err := os.WriteFile(path, []byte(sampleGitleaks), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, issues, err := LoadGitleaks(path, "Gitleaks")
+	if err != nil {
+		t.Fatalf("LoadGitleaks failed: %v", err)
+	}
+	if rules.Group != "Gitleaks" || len(rules.Rule) != 3 {
+		t.Fatalf("expected 3 rules in group Gitleaks, got %+v", rules)
+	}
+
+	rule := rules.Rule[0]
+	if rule.ID != "aws-access-token" || rule.Name != "AWS Access Token" || rule.SecretGroup != 1 || rule.Entropy != 3 {
+		t.Errorf("unexpected rule fields: %+v", rule)
+	}
+	if rule.Engine != "go" || !rule.Sensitive || !rule.Loaded {
+		t.Errorf("expected an enabled sensitive go rule, got %+v", rule)
+	}
+	if len(rule.Keywords) != 1 || rule.Keywords[0] != "akia" {
+		t.Errorf("expected keywords to be imported, got %v", rule.Keywords)
+	}
+	if len(rule.Allowlist.Regexes) != 1 || len(rule.Allowlist.Paths) != 1 {
+		t.Errorf("expected rule and global allowlists to be merged, got %+v", rule.Allowlist)
+	}
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	joined := strings.Join(messages, "\n")
+	for _, expected := range []string{
+		"[pkcs12-file] path-only rule skipped",
+		"[aws-access-token] regex is case-sensitive",
+		"[quoted-token] secretGroup unset",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("expected issue %q, got:\n%s", expected, joined)
+		}
+	}
+	for _, unexpected := range []string{"[generic-api-key]", "[quoted-token] regex is case-sensitive"} {
+		if strings.Contains(joined, unexpected) {
+			t.Errorf("unexpected issue %q, got:\n%s", unexpected, joined)
+		}
+	}
+
+	// 未设置 secretGroup 时与 gitleaks 一样提取捕获组, 熵按提取值计算
+	apiKey := rules.Rule[1]
+	if apiKey.SecretGroup != 1 || rules.Rule[2].SecretGroup != 1 {
+		t.Fatalf("expected secret_group 1 for rules with capture groups, got %d and %d", apiKey.SecretGroup, rules.Rule[2].SecretGroup)
+	}
+	matcher, err := apiKey.CompileMatcher()
+	if err != nil {
+		t.Fatalf("CompileMatcher failed: %v", err)
+	}
+	match, err := matcher.FindStringMatch("api_key = 0123456789abcdef0123456789abcdef")
+	if err != nil || match == nil {
+		t.Fatalf("expected a match, got %v %v", match, err)
+	}
+	if value := apiKey.ExtractValue(match); value != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("expected the capture group to be extracted, got %q", value)
+	}
+}
+
+// TestGitleaksRoundTrip 测试导出 gitleaks 后再导入
+func TestGitleaksRoundTrip(t *testing.T) {
+	config := &baserule.RuleConfig{Rules: []baserule.Rules{{
+		Group: "Sensitive Information",
+		Rule: []baserule.Rule{
+			{Name: "Cloud Key", FRegex: `LTAI[a-z0-9]{12,20}`, Loaded: true, Severity: "critical", Tags: []string{"cloud"}, Keywords: []string{"ltai"}},
+			{Name: "Cloud Key", FRegex: `AKID[a-z0-9]{13,20}`, Loaded: true, Allowlist: baserule.Allowlist{Stopwords: []string{"dummy"}}},
+			{Name: "Email", FRegex: `\w+@(?!example)\w+\.com`, Loaded: true},
+			{Name: "Disabled", FRegex: `x`, Loaded: false},
+		},
+	}}}
+
+	data, issues, err := ExportGitleaks(config)
+	if err != nil {
+		t.Fatalf("ExportGitleaks failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Errorf("expected 3 issues (dropped severity, unsupported regex, disabled rule), got %v", issues)
+	}
+
+	path := filepath.Join(t.TempDir(), "gitleaks.toml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, _, err := LoadGitleaks(path, "Imported")
+	if err != nil {
+		t.Fatalf("LoadGitleaks failed: %v", err)
+	}
+	if len(rules.Rule) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules.Rule))
+	}
+	if rules.Rule[0].ID != "cloud-key" || rules.Rule[1].ID != "cloud-key-2" {
+		t.Errorf("expected generated unique ids, got %s and %s", rules.Rule[0].ID, rules.Rule[1].ID)
+	}
+	if rules.Rule[0].FRegex != `(?i)LTAI[a-z0-9]{12,20}` {
+		t.Errorf("expected case-insensitive regex, got %s", rules.Rule[0].FRegex)
+	}
+	if len(rules.Rule[0].Keywords) != 1 || rules.Rule[0].Keywords[0] != "ltai" {
+		t.Errorf("expected keywords to survive the round trip, got %v", rules.Rule[0].Keywords)
+	}
+	if len(rules.Rule[1].Allowlist.Stopwords) != 1 {
+		t.Errorf("expected allowlist to survive the round trip, got %+v", rules.Rule[1].Allowlist)
+	}
+}
@@ -0,0 +1,97 @@
+package ruleconv
+
+import (
+	"fmt"
+	"strings"
+
+	"privacycheck/internal/baserule"
+)
+
+// haeRule HAE 规则(与 HAE 插件规则文件字段一致)
+type haeRule struct {
+	Name       string `yaml:"name"`
+	Loaded     bool   `yaml:"loaded"`
+	FRegex     string `yaml:"f_regex"`
+	SRegex     string `yaml:"s_regex"`
+	Format     string `yaml:"format"`
+	Color      string `yaml:"color"`
+	Scope      string `yaml:"scope"`
+	Engine     string `yaml:"engine"`
+	Sensitive  bool   `yaml:"sensitive"`
+	SampleCode string `yaml:"sample_code,omitempty"`
+}
+
+// haeRules HAE 规则组
+type haeRules struct {
+	Group string    `yaml:"group"`
+	Rule  []haeRule `yaml:"rule"`
+}
+
+// haeConfig HAE 规则文件
+type haeConfig struct {
+	Rules []haeRules `yaml:"rules"`
+}
+
+// haeKeptFields 导出为 HAE 时可以表示的规则字段
+var haeKeptFields = map[string]bool{
+	"name": true, "loaded": true, "f_regex": true, "s_regex": true, "format": true, "color": true,
+	"scope": true, "engine": true, "sensitive": true, "sample_code": true,
+}
+
+// haeSeverityColors 严重等级对应的 HAE 高亮颜色
+var haeSeverityColors = map[string]string{
+	"critical": "red",
+	"high":     "orange",
+	"medium":   "yellow",
+	"low":      "green",
+	"info":     "gray",
+}
+
+// ExportHAE 将规则导出为 HAE YAML
+// 未配置的 format/color/scope 使用 HAE 默认值, 颜色由严重等级推导; 引擎统一转换为 HAE 的 nfa/dfa
+func ExportHAE(config *baserule.RuleConfig) ([]byte, []Issue, error) {
+	var issues []Issue
+	var output haeConfig
+
+	for _, group := range config.Rules {
+		exportedGroup := haeRules{Group: group.Group}
+		for _, rule := range group.Rule {
+			exported := haeRule{
+				Name:       rule.Name,
+				Loaded:     rule.Loaded,
+				FRegex:     rule.FRegex,
+				SRegex:     rule.SRegex,
+				Format:     rule.Format,
+				Color:      rule.Color,
+				Scope:      rule.Scope,
+				Engine:     strings.ToLower(rule.Engine),
+				Sensitive:  rule.Sensitive,
+				SampleCode: rule.SampleCode,
+			}
+			if exported.Format == "" {
+				exported.Format = "{0}"
+			}
+			if exported.Color == "" {
+				exported.Color = haeSeverityColors[rule.GetSeverity()]
+			}
+			if exported.Scope == "" {
+				exported.Scope = "any"
+			}
+			if exported.Engine != "dfa" {
+				exported.Engine = "nfa"
+			}
+			exportedGroup.Rule = append(exportedGroup.Rule, exported)
+
+			if dropped := droppedFields(rule, haeKeptFields); len(dropped) > 0 {
+				issues = append(issues, Issue{Rule: ruleIdentifier(group.Group, rule), Message: fmt.Sprintf("fields dropped: %s", strings.Join(dropped, ", "))})
+			}
+		}
+		output.Rules = append(output.Rules, exportedGroup)
+	}
+
+	data, err := marshalYAML(output, false)
+	if err != nil {
+		return nil, issues, fmt.Errorf("failed to encode HAE rules: %w", err)
+	}
+	return data, issues, nil
+}
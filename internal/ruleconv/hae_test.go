@@ -0,0 +1,70 @@
+package ruleconv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestExportHAE 测试导出 HAE 规则并重新加载
+func TestExportHAE(t *testing.T) {
+	config := &baserule.RuleConfig{Rules: []baserule.Rules{{
+		Group: "People Information",
+		Rule: []baserule.Rule{
+			{ID: "bank-card", Name: "Bank Card Number", FRegex: `[^0-9](62\d{14,17})[^0-9]`, Loaded: true, Engine: "go", Sensitive: true, Validator: "luhn"},
+			{Name: "Email", FRegex: `\w+@\w+\.com`, Loaded: true, Engine: "dfa", Color: "yellow", Scope: "response"},
+		},
+	}}}
+
+	data, issues, err := ExportHAE(config)
+	if err != nil {
+		t.Fatalf("ExportHAE failed: %v", err)
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "id, validator") {
+		t.Errorf("expected dropped id and validator to be reported, got %v", issues)
+	}
+
+	path := filepath.Join(t.TempDir(), "hae.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := baserule.LoadRulesYaml(path)
+	if err != nil {
+		t.Fatalf("LoadRulesYaml failed: %v", err)
+	}
+
+	card, email := loaded.Rules[0].Rule[0], loaded.Rules[0].Rule[1]
+	if card.Engine != "nfa" || card.Format != "{0}" || card.Color != "orange" || card.Scope != "any" || card.ID != "" {
+		t.Errorf("unexpected exported rule: %+v", card)
+	}
+	if email.Engine != "dfa" || email.Color != "yellow" || email.Scope != "response" || email.FRegex != `\w+@\w+\.com` {
+		t.Errorf("unexpected exported rule: %+v", email)
+	}
+}
+
+// TestExportPrivacyCheck 测试导出 PrivacyCheck 规则时省略空字段
+func TestExportPrivacyCheck(t *testing.T) {
+	config := &baserule.RuleConfig{Rules: []baserule.Rules{{
+		Group: "Test",
+		Rule:  []baserule.Rule{{ID: "test", Name: "Test", FRegex: "abc", Loaded: false, Entropy: 3.5}},
+	}}}
+
+	data, _, err := Export(config, FormatPrivacyCheck)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	output := string(data)
+	for _, expected := range []string{"id: test", "loaded: false", "entropy: 3.5"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"s_regex", "secret_group", "allowlist", "include"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("unexpected %q in output:\n%s", unexpected, output)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ruleeval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// 规则评估变化类型
+const (
+	ChangeAdded     = "added"     // 基线中不存在的规则
+	ChangeRemoved   = "removed"   // 本次评估中不存在的规则
+	ChangeImproved  = "improved"  // 准确率与召回率均未下降且至少一项提升
+	ChangeRegressed = "regressed" // 准确率或召回率下降
+	ChangeChanged   = "changed"   // TP/FP/FN 变化但准确率与召回率不变
+)
+
+// RuleChange 规则相对基线评估的变化
+type RuleChange struct {
+	Rule   string       `json:"rule"`
+	Change string       `json:"change"`
+	Before *RuleMetrics `json:"before,omitempty"`
+	After  *RuleMetrics `json:"after,omitempty"`
+}
+
+// LoadEvaluation 加载之前保存的评估结果
+func LoadEvaluation(evaluationFile string) (*Evaluation, error) {
+	data, err := os.ReadFile(evaluationFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evaluation file %s: %w", evaluationFile, err)
+	}
+	var evaluation Evaluation
+	if err := json.Unmarshal(data, &evaluation); err != nil {
+		return nil, fmt.Errorf("failed to parse evaluation file %s: %w", evaluationFile, err)
+	}
+	return &evaluation, nil
+}
+
+// Diff 比较本次评估与基线评估, 返回指标发生变化的规则
+func Diff(baseline, current *Evaluation) []RuleChange {
+	before := make(map[string]RuleMetrics)
+	for _, metric := range baseline.Rules {
+		before[metric.Rule] = metric
+	}
+	after := make(map[string]RuleMetrics)
+	for _, metric := range current.Rules {
+		after[metric.Rule] = metric
+	}
+
+	var changes []RuleChange
+	for key, afterMetric := range after {
+		beforeMetric, ok := before[key]
+		if !ok {
+			changes = append(changes, RuleChange{Rule: key, Change: ChangeAdded, After: &afterMetric})
+			continue
+		}
+		if change := compareMetrics(beforeMetric, afterMetric); change != "" {
+			changes = append(changes, RuleChange{Rule: key, Change: change, Before: &beforeMetric, After: &afterMetric})
+		}
+	}
+	for key, beforeMetric := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, RuleChange{Rule: key, Change: ChangeRemoved, Before: &beforeMetric})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Rule < changes[j].Rule })
+	return changes
+}
+
+// compareMetrics 比较两次评估的指标, 无变化时返回空字符串
+func compareMetrics(before, after RuleMetrics) string {
+	if before.TruePositives == after.TruePositives && before.FalsePositives == after.FalsePositives && before.FalseNegatives == after.FalseNegatives {
+		return ""
+	}
+
+	precision := compareRatio(before.Precision, after.Precision)
+	recall := compareRatio(before.Recall, after.Recall)
+	switch {
+	case precision < 0 || recall < 0:
+		return ChangeRegressed
+	case precision > 0 || recall > 0:
+		return ChangeImproved
+	default:
+		return ChangeChanged
+	}
+}
+
+// compareRatio 比较两个比率, 提升返回1, 下降返回-1; 不可计算(null)的比率视为无变化
+func compareRatio(before, after *float64) int {
+	if before == nil || after == nil || *before == *after {
+		return 0
+	}
+	if *after > *before {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,265 @@
+package ruleeval
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/baserule"
+	"privacycheck/internal/scanner"
+)
+
+// Annotation 标注的期望结果
+type Annotation struct {
+	File  string `yaml:"file" json:"file"`                     // 相对语料目录的文件路径
+	Rule  string `yaml:"rule" json:"rule"`                     // 规则ID或规则名称
+	Match string `yaml:"match" json:"match"`                   // 期望的提取值
+	Line  int    `yaml:"line,omitempty" json:"line,omitempty"` // 行号(为0时不比较)
+}
+
+// AnnotationFile 标注文件
+type AnnotationFile struct {
+	Findings []Annotation `yaml:"findings" json:"findings"`
+}
+
+// LoadAnnotations 加载标注文件(YAML或JSON)
+func LoadAnnotations(annotationsFile string) ([]Annotation, error) {
+	var annotationFile AnnotationFile
+	if err := utils.LoadYAML(annotationsFile, &annotationFile); err != nil {
+		return nil, fmt.Errorf("failed to load annotations file %s: %w", annotationsFile, err)
+	}
+	for i, annotation := range annotationFile.Findings {
+		if annotation.File == "" || annotation.Rule == "" || annotation.Match == "" {
+			return nil, fmt.Errorf("annotation #%d: file, rule and match are required", i+1)
+		}
+		annotationFile.Findings[i].File = filepath.ToSlash(filepath.Clean(annotation.File))
+	}
+	return annotationFile.Findings, nil
+}
+
+// Finding 误报或漏报的明细
+type Finding struct {
+	Rule  string `json:"rule"`
+	File  string `json:"file"`
+	Match string `json:"match"`
+	Line  int    `json:"line,omitempty"`
+}
+
+// RuleMetrics 单条规则的评估指标
+type RuleMetrics struct {
+	Rule           string   `json:"rule"` // 规则标识(规则ID, 无ID时为 "组名: 规则名")
+	Group          string   `json:"group,omitempty"`
+	RuleName       string   `json:"rule_name,omitempty"`
+	TruePositives  int      `json:"tp"`
+	FalsePositives int      `json:"fp"`
+	FalseNegatives int      `json:"fn"`
+	Precision      *float64 `json:"precision"` // 无输出结果时为 null
+	Recall         *float64 `json:"recall"`    // 无标注时为 null
+}
+
+// compute 根据 TP/FP/FN 计算准确率与召回率
+func (m *RuleMetrics) compute() {
+	m.Precision, m.Recall = nil, nil
+	if predicted := m.TruePositives + m.FalsePositives; predicted > 0 {
+		precision := float64(m.TruePositives) / float64(predicted)
+		m.Precision = &precision
+	}
+	if expected := m.TruePositives + m.FalseNegatives; expected > 0 {
+		recall := float64(m.TruePositives) / float64(expected)
+		m.Recall = &recall
+	}
+}
+
+// Evaluation 规则包在标注语料上的评估结果
+type Evaluation struct {
+	Corpus         string        `json:"corpus"`
+	Annotations    string        `json:"annotations"`
+	Timestamp      string        `json:"timestamp"`
+	Files          int           `json:"files"`
+	Ignored        int           `json:"ignored_annotations"` // 规则不在评估范围内的标注数量
+	Total          RuleMetrics   `json:"total"`
+	Rules          []RuleMetrics `json:"rules"`
+	FalsePositives []Finding     `json:"false_positives"`
+	FalseNegatives []Finding     `json:"false_negatives"`
+	Changes        []RuleChange  `json:"changes,omitempty"` // 与基线评估的差异
+}
+
+// Evaluate 使用扫描引擎扫描语料目录并与标注比较
+func Evaluate(rules baserule.RuleMap, corpusDir, annotationsFile string, workers int) (*Evaluation, error) {
+	annotations, err := LoadAnnotations(annotationsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := utils.GetFilesWithFilter(corpusDir, nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corpus files: %w", err)
+	}
+
+	instance, err := scanner.NewScanner(rules, &scanner.ScanConfig{Workers: workers})
+	if err != nil {
+		return nil, err
+	}
+	results, err := instance.Scan(files)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := Score(rules, corpusDir, results, annotations)
+	evaluation.Annotations = annotationsFile
+	evaluation.Files = len(files)
+	return evaluation, nil
+}
+
+// ruleRef 参与评估的规则
+type ruleRef struct {
+	key   string
+	group string
+	id    string
+	name  string
+}
+
+// ruleKey 返回规则标识, 优先使用规则ID
+func ruleKey(group, id, name string) string {
+	if id != "" {
+		return id
+	}
+	return group + ": " + name
+}
+
+// matchesRule 判断标注的规则是否为该规则(按规则ID或名称)
+func (r ruleRef) matchesRule(rule string) bool {
+	return rule == r.key || (r.id != "" && rule == r.id) || rule == r.name
+}
+
+// valueMatched 判断输出的提取值是否命中标注值, 提取值包含边界字符时包含标注值即视为命中
+func valueMatched(actual, expected string, exact bool) bool {
+	if exact {
+		return strings.TrimSpace(actual) == strings.TrimSpace(expected)
+	}
+	return strings.Contains(actual, expected)
+}
+
+// Score 将扫描结果与标注比较, 计算每条规则的 TP/FP/FN
+func Score(rules baserule.RuleMap, corpusDir string, results []scanner.ScanResult, annotations []Annotation) *Evaluation {
+	evaluation := &Evaluation{
+		Corpus:         corpusDir,
+		Timestamp:      time.Now().Format("2006-01-02 15:04:05"),
+		Total:          RuleMetrics{Rule: "TOTAL"},
+		FalsePositives: []Finding{},
+		FalseNegatives: []Finding{},
+	}
+
+	// 登记参与评估的规则
+	var refs []ruleRef
+	metrics := make(map[string]*RuleMetrics)
+	for group, groupRules := range rules {
+		for _, rule := range groupRules {
+			ref := ruleRef{key: ruleKey(group, rule.ID, rule.Name), group: group, id: rule.ID, name: rule.Name}
+			refs = append(refs, ref)
+			metrics[ref.key] = &RuleMetrics{Rule: ref.key, Group: group, RuleName: rule.Name}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].key < refs[j].key })
+
+	// 标注按规则归类, 不在评估范围内的标注忽略
+	expected := make(map[string][]Annotation)
+	for _, annotation := range annotations {
+		matched := false
+		for _, ref := range refs {
+			if ref.matchesRule(annotation.Rule) {
+				expected[ref.key] = append(expected[ref.key], annotation)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			evaluation.Ignored++
+		}
+	}
+
+	// 输出结果按规则归类, 规则分析器的分析结果不参与评估
+	actual := make(map[string][]scanner.ScanResult)
+	for _, result := range results {
+		if result.Analysis != "" {
+			continue
+		}
+		key := ruleKey(result.Group, result.RuleID, result.RuleName)
+		if _, ok := metrics[key]; ok {
+			actual[key] = append(actual[key], result)
+		}
+	}
+
+	for _, ref := range refs {
+		metric := metrics[ref.key]
+		ruleResults := actual[ref.key]
+		sort.SliceStable(ruleResults, func(i, j int) bool {
+			if ruleResults[i].File != ruleResults[j].File {
+				return ruleResults[i].File < ruleResults[j].File
+			}
+			return ruleResults[i].Position < ruleResults[j].Position
+		})
+
+		ruleAnnotations := expected[ref.key]
+		consumed := make([]bool, len(ruleAnnotations))
+		matched := make([]bool, len(ruleResults))
+
+		// 先精确匹配, 再按包含关系匹配
+		for _, exact := range []bool{true, false} {
+			for i, result := range ruleResults {
+				if matched[i] {
+					continue
+				}
+				file := relativePath(corpusDir, result.File)
+				for j, annotation := range ruleAnnotations {
+					if consumed[j] || annotation.File != file || (annotation.Line > 0 && annotation.Line != result.LineNumber) {
+						continue
+					}
+					if valueMatched(result.Match, annotation.Match, exact) {
+						consumed[j], matched[i] = true, true
+						break
+					}
+				}
+			}
+		}
+
+		for i, result := range ruleResults {
+			if matched[i] {
+				metric.TruePositives++
+				continue
+			}
+			metric.FalsePositives++
+			evaluation.FalsePositives = append(evaluation.FalsePositives, Finding{
+				Rule: ref.key, File: relativePath(corpusDir, result.File), Match: result.Match, Line: result.LineNumber,
+			})
+		}
+		for j, annotation := range ruleAnnotations {
+			if !consumed[j] {
+				metric.FalseNegatives++
+				evaluation.FalseNegatives = append(evaluation.FalseNegatives, Finding{
+					Rule: ref.key, File: annotation.File, Match: annotation.Match, Line: annotation.Line,
+				})
+			}
+		}
+
+		metric.compute()
+		evaluation.Rules = append(evaluation.Rules, *metric)
+		evaluation.Total.TruePositives += metric.TruePositives
+		evaluation.Total.FalsePositives += metric.FalsePositives
+		evaluation.Total.FalseNegatives += metric.FalseNegatives
+	}
+	evaluation.Total.compute()
+
+	return evaluation
+}
+
+// relativePath 返回相对语料目录的文件路径(使用 / 分隔)
+func relativePath(corpusDir, file string) string {
+	if rel, err := filepath.Rel(corpusDir, file); err == nil {
+		file = rel
+	}
+	return filepath.ToSlash(filepath.Clean(file))
+}
@@ -0,0 +1,107 @@
+package ruleeval
+
+import (
+	"path/filepath"
+	"testing"
+
+	"privacycheck/internal/baserule"
+	"privacycheck/internal/scanner"
+)
+
+// testRules 评估测试使用的规则
+var testRules = baserule.RuleMap{
+	"Contact": {
+		{ID: "email", Name: "Email"},
+		{Name: "Phone"},
+	},
+}
+
+// TestScore 测试 TP/FP/FN 统计与准确率、召回率计算
+func TestScore(t *testing.T) {
+	corpus := "corpus"
+	results := []scanner.ScanResult{
+		{File: filepath.Join(corpus, "a.txt"), Group: "Contact", RuleID: "email", RuleName: "Email", Match: "a@example.com", LineNumber: 1},
+		{File: filepath.Join(corpus, "a.txt"), Group: "Contact", RuleID: "email", RuleName: "Email", Match: "fake@test.invalid", LineNumber: 2},
+		{File: filepath.Join(corpus, "b.txt"), Group: "Contact", RuleName: "Phone", Match: " 13812345678 ", LineNumber: 3},
+	}
+	annotations := []Annotation{
+		{File: "a.txt", Rule: "email", Match: "a@example.com"},
+		{File: "b.txt", Rule: "Email", Match: "b@example.com"},
+		{File: "b.txt", Rule: "Phone", Match: "13812345678", Line: 3},
+		{File: "b.txt", Rule: "unknown", Match: "x"},
+	}
+
+	evaluation := Score(testRules, corpus, results, annotations)
+	if evaluation.Ignored != 1 {
+		t.Errorf("ignored annotations = %d, expected 1", evaluation.Ignored)
+	}
+	if len(evaluation.Rules) != 2 {
+		t.Fatalf("rules = %v, expected 2", evaluation.Rules)
+	}
+
+	email, phone := evaluation.Rules[1], evaluation.Rules[0]
+	if phone.Rule != "Contact: Phone" || phone.TruePositives != 1 || phone.FalsePositives != 0 || phone.FalseNegatives != 0 {
+		t.Errorf("phone metrics = %+v", phone)
+	}
+	if email.Rule != "email" || email.TruePositives != 1 || email.FalsePositives != 1 || email.FalseNegatives != 1 {
+		t.Errorf("email metrics = %+v", email)
+	}
+	if *email.Precision != 0.5 || *email.Recall != 0.5 {
+		t.Errorf("email precision/recall = %v/%v, expected 0.5/0.5", *email.Precision, *email.Recall)
+	}
+	if len(evaluation.FalsePositives) != 1 || evaluation.FalsePositives[0].Match != "fake@test.invalid" || evaluation.FalsePositives[0].File != "a.txt" {
+		t.Errorf("false positives = %+v", evaluation.FalsePositives)
+	}
+	if len(evaluation.FalseNegatives) != 1 || evaluation.FalseNegatives[0].Match != "b@example.com" {
+		t.Errorf("false negatives = %+v", evaluation.FalseNegatives)
+	}
+	if total := evaluation.Total; total.TruePositives != 2 || total.FalsePositives != 1 || total.FalseNegatives != 1 {
+		t.Errorf("total = %+v", total)
+	}
+
+	// 无结果且无标注的规则不可计算准确率与召回率
+	empty := Score(testRules, corpus, nil, nil)
+	if empty.Rules[0].Precision != nil || empty.Rules[0].Recall != nil {
+		t.Errorf("expected nil precision and recall, got %+v", empty.Rules[0])
+	}
+}
+
+// TestDiff 测试与基线评估的对比
+func TestDiff(t *testing.T) {
+	metrics := func(rule string, tp, fp, fn int) RuleMetrics {
+		metric := RuleMetrics{Rule: rule, TruePositives: tp, FalsePositives: fp, FalseNegatives: fn}
+		metric.compute()
+		return metric
+	}
+	baseline := &Evaluation{Rules: []RuleMetrics{
+		metrics("same", 1, 0, 0),
+		metrics("better", 1, 1, 1),
+		metrics("worse", 2, 0, 0),
+		metrics("scaled", 1, 1, 0),
+		metrics("removed", 1, 0, 0),
+	}}
+	current := &Evaluation{Rules: []RuleMetrics{
+		metrics("same", 1, 0, 0),
+		metrics("better", 2, 0, 0),
+		metrics("worse", 2, 1, 0),
+		metrics("scaled", 2, 2, 0),
+		metrics("added", 0, 1, 0),
+	}}
+
+	expected := map[string]string{
+		"added":   ChangeAdded,
+		"better":  ChangeImproved,
+		"removed": ChangeRemoved,
+		"scaled":  ChangeChanged,
+		"worse":   ChangeRegressed,
+	}
+	changes := Diff(baseline, current)
+	if len(changes) != len(expected) {
+		t.Fatalf("Diff() = %+v, expected %d changes", changes, len(expected))
+	}
+	for _, change := range changes {
+		if change.Change != expected[change.Rule] {
+			t.Errorf("change of %s = %s, expected %s", change.Rule, change.Change, expected[change.Rule])
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package ruletest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/baserule"
+)
+
+// engineDiffMaxMatches 单个输入上每个引擎最多收集的匹配数量
+const engineDiffMaxMatches = 10000
+
+// DiffInput 引擎差异测试的输入
+type DiffInput struct {
+	Name string // 输入描述(样例序号或语料文件路径)
+	Text string // 输入内容
+}
+
+// EngineDiff 规则在 Go(RE2) 与 Java(regexp2) 引擎下匹配结果不一致的输入
+type EngineDiff struct {
+	Rule     string   `json:"rule"`      // 规则标识
+	Engine   string   `json:"engine"`    // 规则实际使用的引擎
+	Input    string   `json:"input"`     // 出现差异的输入
+	GoOnly   []string `json:"go_only"`   // 仅 Go 引擎提取到的值
+	JavaOnly []string `json:"java_only"` // 仅 Java 引擎提取到的值
+}
+
+// String 返回差异描述
+func (d EngineDiff) String() string {
+	return fmt.Sprintf("%s: go only %s, java only %s", d.Input, formatValues(d.GoOnly), formatValues(d.JavaOnly))
+}
+
+// SampleInputs 返回规则自带的测试输入: sample_code、正样例与负样例
+func SampleInputs(rule baserule.Rule) []DiffInput {
+	var inputs []DiffInput
+	if rule.SampleCode != "" {
+		inputs = append(inputs, DiffInput{Name: "sample_code", Text: rule.SampleCode})
+	}
+	for i, sample := range rule.Samples {
+		inputs = append(inputs, DiffInput{Name: fmt.Sprintf("samples #%d", i+1), Text: sample.Text})
+	}
+	for i, sample := range rule.NegativeSamples {
+		inputs = append(inputs, DiffInput{Name: fmt.Sprintf("negative_samples #%d", i+1), Text: sample})
+	}
+	return inputs
+}
+
+// LoadCorpus 读取语料目录下的文件作为测试输入, limitSize 为文件大小限制(MB)
+func LoadCorpus(corpusDir string, limitSize int) ([]DiffInput, error) {
+	files, err := utils.GetFilesWithFilter(corpusDir, nil, nil, limitSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corpus files: %w", err)
+	}
+	sort.Strings(files)
+
+	var inputs []DiffInput
+	for _, file := range files {
+		fileInfo, err := utils.PathToFileInfo(file)
+		if err != nil || fileInfo.Size == 0 {
+			continue
+		}
+		content, err := utils.ReadFileWithEncoding(file, fileInfo.Encoding)
+		if err != nil {
+			logging.Warnf("failed to read corpus file %s: %v", file, err)
+			continue
+		}
+		inputs = append(inputs, DiffInput{Name: file, Text: content})
+	}
+	return inputs, nil
+}
+
+// DiffEngines 使用 Go 与 Java 两种引擎分别对输入执行规则的扫描正则, 返回提取值不一致的输入
+// 只有两种引擎都能编译的规则才能比较, 否则返回错误
+func DiffEngines(ruleIdentifier string, rule baserule.Rule, inputs []DiffInput) ([]EngineDiff, error) {
+	pattern := rule.ScanPattern()
+	goMatcher, err := baserule.NewGoRegexMatcher(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("go engine: %w", err)
+	}
+	javaMatcher, err := baserule.NewJavaRegexMatcher(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("java engine: %w", err)
+	}
+
+	engine := ""
+	if matcher, err := rule.CompileMatcher(); err == nil {
+		engine = string(baserule.MatcherEngine(matcher))
+	}
+
+	var diffs []EngineDiff
+	for _, input := range inputs {
+		goValues, err := collectValues(rule, goMatcher, input.Text)
+		if err != nil {
+			continue
+		}
+		javaValues, err := collectValues(rule, javaMatcher, input.Text)
+		if err != nil {
+			logging.Warnf("[%s] java engine failed on %s: %v", ruleIdentifier, input.Name, err)
+			continue
+		}
+		goOnly, javaOnly := diffValues(goValues, javaValues)
+		if len(goOnly) > 0 || len(javaOnly) > 0 {
+			diffs = append(diffs, EngineDiff{Rule: ruleIdentifier, Engine: engine, Input: input.Name, GoOnly: goOnly, JavaOnly: javaOnly})
+		}
+	}
+	return diffs, nil
+}
+
+// collectValues 遍历匹配并返回提取值
+func collectValues(rule baserule.Rule, matcher baserule.RegexMatcher, text string) ([]string, error) {
+	var values []string
+	match, err := matcher.FindStringMatch(text)
+	for match != nil && err == nil && len(values) < engineDiffMaxMatches {
+		values = append(values, rule.ExtractValue(match))
+		match, err = match.FindNextMatch()
+	}
+	return values, err
+}
+
+// diffValues 比较两组提取值(多重集合), 返回各自独有的值
+func diffValues(left, right []string) (leftOnly, rightOnly []string) {
+	counts := make(map[string]int)
+	for _, value := range left {
+		counts[value]++
+	}
+	for _, value := range right {
+		counts[value]--
+	}
+	for _, value := range left {
+		if counts[value] > 0 {
+			leftOnly = append(leftOnly, value)
+			counts[value]--
+		}
+	}
+	for _, value := range right {
+		if counts[value] < 0 {
+			rightOnly = append(rightOnly, value)
+			counts[value]++
+		}
+	}
+	return leftOnly, rightOnly
+}
@@ -0,0 +1,40 @@
+package ruletest
+
+import (
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestDiffEngines 测试 Go 与 Java 引擎匹配差异检测
+func TestDiffEngines(t *testing.T) {
+	inputs := []DiffInput{
+		{Name: "ascii", Text: "id=12345"},
+		{Name: "unicode", Text: "é12345 ١٢٣"},
+	}
+
+	// \w 与 \d 在 regexp2 中匹配 Unicode 字符
+	rule := baserule.Rule{Name: "Digits", FRegex: `\b\d+`}
+	diffs, err := DiffEngines("Test: Digits", rule, inputs)
+	if err != nil {
+		t.Fatalf("DiffEngines failed: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Input != "unicode" {
+		t.Fatalf("DiffEngines() = %v, expected one diff on unicode input", diffs)
+	}
+	if len(diffs[0].GoOnly) != 1 || diffs[0].GoOnly[0] != "12345" || len(diffs[0].JavaOnly) != 1 || diffs[0].JavaOnly[0] != "١٢٣" {
+		t.Errorf("diff = %v", diffs[0])
+	}
+
+	// 两种引擎结果一致
+	rule = baserule.Rule{Name: "Id", FRegex: `id=([0-9]+)`, SecretGroup: 1}
+	if diffs, err := DiffEngines("Test: Id", rule, inputs); err != nil || len(diffs) != 0 {
+		t.Errorf("DiffEngines() = %v, %v, expected no diff", diffs, err)
+	}
+
+	// 只有 Java 引擎能编译的规则无法比较
+	rule = baserule.Rule{Name: "Lookahead", FRegex: `\d+(?=px)`}
+	if _, err := DiffEngines("Test: Lookahead", rule, inputs); err == nil {
+		t.Errorf("expected error for pattern not supported by go engine")
+	}
+}
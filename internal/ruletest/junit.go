@@ -0,0 +1,118 @@
+package ruletest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// junitTestSuites JUnit XML 根节点
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite 规则组对应的测试套件
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase 规则对应的测试用例
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Time      string         `xml:"time,attr"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped  `xml:"skipped,omitempty"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+// junitFailure 测试失败信息
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSkipped 测试跳过信息
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// junitSeconds 将毫秒格式化为 JUnit 使用的秒数
+func junitSeconds(ms float64) string {
+	return fmt.Sprintf("%.3f", ms/1000)
+}
+
+// ToJUnit 将测试结果转换为 JUnit XML, 每个规则组为一个 testsuite, 每条规则为一个 testcase
+func (s TestSummary) ToJUnit() ([]byte, error) {
+	root := junitTestSuites{
+		Name:     s.RulesFile,
+		Tests:    s.Total,
+		Failures: s.Failed,
+		Skipped:  s.Skipped,
+		Time:     junitSeconds(s.DurationMs),
+	}
+
+	suiteIndex := make(map[string]int)
+	suiteDurations := make(map[string]float64)
+	for _, result := range s.Results {
+		index, ok := suiteIndex[result.Group]
+		if !ok {
+			index = len(root.Suites)
+			suiteIndex[result.Group] = index
+			root.Suites = append(root.Suites, junitTestSuite{Name: result.Group})
+		}
+		suite := &root.Suites[index]
+
+		testCase := junitTestCase{
+			ClassName: result.Group,
+			Name:      result.RuleName,
+			Time:      junitSeconds(result.DurationMs),
+			SystemOut: strings.Join(result.Warnings, "\n"),
+		}
+		for _, failure := range result.Failures {
+			testCase.Failures = append(testCase.Failures, junitFailure{Type: failure.Type, Message: failure.Message, Text: failure.Message})
+		}
+		switch result.Status {
+		case TestStatusFailed:
+			suite.Failures++
+		case TestStatusSkipped:
+			suite.Skipped++
+			testCase.Skipped = &junitSkipped{Message: result.SkipReason}
+		}
+
+		suite.Tests++
+		suiteDurations[result.Group] += result.DurationMs
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for i := range root.Suites {
+		root.Suites[i].Time = junitSeconds(suiteDurations[root.Suites[i].Name])
+	}
+
+	content, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
+
+// SaveJUnit 将测试结果保存为 JUnit XML 文件
+func (s TestSummary) SaveJUnit(junitFile string) error {
+	content, err := s.ToJUnit()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(junitFile, content, 0644)
+}
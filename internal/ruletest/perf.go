@@ -0,0 +1,140 @@
+package ruletest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+const (
+	adversarialInputSize = 4096 // 对抗输入中重复字符的长度
+	maxMatchErrorLength  = 200  // 记录的匹配错误的最大长度
+)
+
+// adversarialInput 用于测量规则耗时的对抗输入
+type adversarialInput struct {
+	name string // 输入描述
+	text string // 输入内容
+}
+
+// RulePerf 规则在对抗输入下的耗时
+type RulePerf struct {
+	Rule     string        // 规则标识
+	Engine   string        // 实际使用的引擎
+	Duration time.Duration // 最长耗时
+	Input    string        // 最长耗时对应的输入描述
+	TimedOut bool          // 是否超出耗时预算
+	Error    string        // 匹配错误(如 regexp2 超时)
+}
+
+// RuleLint 规则的性能检查结果
+type RuleLint struct {
+	Rule         string         // 规则标识
+	Engine       string         // 实际使用的引擎
+	Findings     []RedosFinding // 回溯风险(仅 java 引擎)
+	GoCompatible bool           // 被指定为 java 引擎但可以使用 Go 引擎编译
+	Perf         RulePerf       // 对抗输入耗时
+}
+
+// LintRule 对规则进行性能检查: 回溯风险静态分析、引擎选择检查与对抗输入耗时测量
+func LintRule(ruleIdentifier string, rule baserule.Rule, budget time.Duration) (RuleLint, error) {
+	lint := RuleLint{Rule: ruleIdentifier}
+
+	matcher, err := rule.CompileMatcher()
+	if err != nil {
+		return lint, err
+	}
+	lint.Engine = string(baserule.MatcherEngine(matcher))
+
+	analysis := analyzePattern(rule.FRegex)
+	if lint.Engine == string(baserule.RegexEngineJava) {
+		lint.Findings = analysis.findings
+		if rule.Engine != "" {
+			_, goErr := regexp.Compile(rule.ScanPattern())
+			lint.GoCompatible = goErr == nil
+		}
+		// 测量时使用耗时预算作为 regexp2 的超时时间
+		if javaMatcher, ok := matcher.(*baserule.JavaRegexMatcher); ok {
+			javaMatcher.SetMatchTimeout(budget)
+		}
+	}
+
+	lint.Perf = measureRule(ruleIdentifier, lint.Engine, matcher, adversarialInputs(rule, analysis.repeatAtoms), budget)
+	return lint, nil
+}
+
+// adversarialInputs 生成对抗输入: 对每个带无界量词的原子重复可匹配字符并以无法匹配的字符结尾, 另外加入重复的样例代码与常见字符
+func adversarialInputs(rule baserule.Rule, repeatAtoms []string) []adversarialInput {
+	var inputs []adversarialInput
+	seen := make(map[string]bool)
+	add := func(name, text string) {
+		if text != "" && !seen[text] {
+			seen[text] = true
+			inputs = append(inputs, adversarialInput{name: name, text: text})
+		}
+	}
+
+	for _, atom := range repeatAtoms {
+		char := atomProbeChar(atom)
+		if char == "" {
+			continue
+		}
+		run := strings.Repeat(char, adversarialInputSize)
+		add(fmt.Sprintf("%q x %d + \\x00", char, adversarialInputSize), run+"\x00")
+		add(fmt.Sprintf("%q x %d + \"!\"", char, adversarialInputSize), run+"!")
+	}
+
+	if rule.SampleCode != "" {
+		count := adversarialInputSize/len(rule.SampleCode) + 1
+		add(fmt.Sprintf("sample_code x %d", count), strings.Repeat(rule.SampleCode+" ", count))
+	}
+	for _, char := range []string{"a", "0", " ", "\""} {
+		add(fmt.Sprintf("%q x %d", char, adversarialInputSize), strings.Repeat(char, adversarialInputSize))
+	}
+
+	return inputs
+}
+
+// measureRule 依次使用对抗输入执行完整匹配(遍历所有结果), 记录最长耗时, 超出预算后停止测量
+func measureRule(ruleIdentifier, engine string, matcher baserule.RegexMatcher, inputs []adversarialInput, budget time.Duration) RulePerf {
+	perf := RulePerf{Rule: ruleIdentifier, Engine: engine}
+
+	for _, input := range inputs {
+		start := time.Now()
+		match, err := matcher.FindStringMatch(input.text)
+		for match != nil && err == nil && time.Since(start) <= budget {
+			match, err = match.FindNextMatch()
+		}
+		elapsed := time.Since(start)
+
+		if elapsed > perf.Duration {
+			perf.Duration = elapsed
+			perf.Input = input.name
+		}
+		if err != nil || elapsed > budget {
+			perf.TimedOut = true
+			perf.Input = input.name
+			if err != nil {
+				perf.Error = matchError(err)
+			}
+			break
+		}
+	}
+
+	return perf
+}
+
+// matchError 返回匹配错误的说明, 去掉 regexp2 超时错误中附带的完整输入(输入描述已记录在 Input 中)
+func matchError(err error) string {
+	message := err.Error()
+	if index := strings.Index(message, " on input "); index >= 0 {
+		message = message[:index]
+	}
+	if len(message) > maxMatchErrorLength {
+		message = message[:maxMatchErrorLength] + "..."
+	}
+	return message
+}
@@ -0,0 +1,303 @@
+package ruletest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 回溯风险类型
+const (
+	RedosNestedQuantifier    = "nested_quantifier"    // 嵌套的无界量词, 如 (a+)+
+	RedosOverlappingAdjacent = "overlapping_adjacent" // 相邻的无界量词匹配相同字符, 如 \w+\w*
+)
+
+// RedosFinding 静态分析发现的灾难性回溯风险
+type RedosFinding struct {
+	Kind     string // 风险类型
+	Fragment string // 存在风险的正则片段
+}
+
+// String 返回风险描述
+func (f RedosFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Kind, f.Fragment)
+}
+
+// patternAtom 正则中的原子(字符、字符类、转义或分组)
+type patternAtom struct {
+	text      string // 原子文本(不含量词)
+	group     bool   // 是否为分组
+	unbounded bool   // 是否带有无界量词(* + {n,})
+	quantText string // 原子文本(含量词)
+}
+
+// groupFrame 分析过程中的分组状态
+type groupFrame struct {
+	start          int  // 分组起始位置
+	innerUnbounded bool // 分组内部是否存在无界量词
+	atomic         bool // 是否为原子分组或断言(不会回溯进入)
+}
+
+// patternAnalysis 正则静态分析结果
+type patternAnalysis struct {
+	findings    []RedosFinding
+	repeatAtoms []string // 带无界量词的单字符原子, 用于生成对抗输入
+}
+
+// AnalyzeBacktracking 静态分析正则中可能导致灾难性回溯的结构
+// 仅用于回溯型引擎(java), 分析基于词法扫描, 结果为启发式判断
+func AnalyzeBacktracking(pattern string) []RedosFinding {
+	return analyzePattern(pattern).findings
+}
+
+// analyzePattern 扫描正则, 识别原子与量词
+func analyzePattern(pattern string) patternAnalysis {
+	var result patternAnalysis
+	stack := []groupFrame{{start: 0}}
+	var prev *patternAtom
+
+	for i := 0; i < len(pattern); {
+		var atom patternAtom
+		switch c := pattern[i]; c {
+		case '\\':
+			end := escapeEnd(pattern, i)
+			atom = patternAtom{text: pattern[i:end]}
+			i = end
+		case '[':
+			end := classEnd(pattern, i)
+			atom = patternAtom{text: pattern[i:end]}
+			i = end
+		case '(':
+			frame := groupFrame{start: i}
+			end := i + 1
+			if strings.HasPrefix(pattern[i:], "(?") {
+				// 内联标志 (?i) 不构成分组
+				if flagsEnd := inlineFlagsEnd(pattern, i); flagsEnd > 0 {
+					i = flagsEnd
+					continue
+				}
+				end = groupPrefixEnd(pattern, i)
+				switch pattern[i:end] {
+				case "(?>", "(?=", "(?!", "(?<=", "(?<!":
+					frame.atomic = true
+				}
+			}
+			stack = append(stack, frame)
+			prev = nil
+			i = end
+			continue
+		case ')':
+			if len(stack) == 1 {
+				i++
+				continue
+			}
+			frame := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			i++
+
+			quantEnd, unbounded, possessive := quantifierEnd(pattern, i)
+			atom = patternAtom{text: pattern[frame.start:i], group: true, quantText: pattern[frame.start:quantEnd]}
+			atom.unbounded = unbounded && !possessive
+			nested := frame.innerUnbounded && !frame.atomic
+			if atom.unbounded && nested {
+				result.findings = append(result.findings, RedosFinding{Kind: RedosNestedQuantifier, Fragment: atom.quantText})
+			}
+			// 内部或自身的无界量词传递给外层分组
+			if atom.unbounded || nested {
+				stack[len(stack)-1].innerUnbounded = true
+			}
+			i = quantEnd
+			prev = &atom
+			continue
+		case '|':
+			prev = nil
+			i++
+			continue
+		case '^', '$':
+			prev = nil
+			i++
+			continue
+		default:
+			atom = patternAtom{text: pattern[i : i+1]}
+			i++
+		}
+
+		quantEnd, unbounded, possessive := quantifierEnd(pattern, i)
+		atom.quantText = atom.text + pattern[i:quantEnd]
+		atom.unbounded = unbounded && !possessive
+		i = quantEnd
+
+		if atom.unbounded {
+			stack[len(stack)-1].innerUnbounded = true
+			result.repeatAtoms = append(result.repeatAtoms, atom.text)
+			if prev != nil && prev.unbounded && !prev.group && atomsOverlap(prev.text, atom.text) {
+				result.findings = append(result.findings, RedosFinding{Kind: RedosOverlappingAdjacent, Fragment: prev.quantText + atom.quantText})
+			}
+		}
+		prev = &atom
+	}
+
+	return result
+}
+
+// escapeEnd 返回转义序列的结束位置
+func escapeEnd(pattern string, i int) int {
+	if i+1 >= len(pattern) {
+		return len(pattern)
+	}
+	end := i + 2
+	switch pattern[i+1] {
+	case 'p', 'P', 'x', 'u':
+		if end < len(pattern) && pattern[end] == '{' {
+			if closing := strings.IndexByte(pattern[end:], '}'); closing >= 0 {
+				return end + closing + 1
+			}
+		}
+		if pattern[i+1] == 'x' {
+			return min(end+2, len(pattern))
+		}
+		if pattern[i+1] == 'u' {
+			return min(end+4, len(pattern))
+		}
+	}
+	return end
+}
+
+// classEnd 返回字符类的结束位置, 支持转义与嵌套字符类
+func classEnd(pattern string, i int) int {
+	depth := 0
+	for j := i; j < len(pattern); j++ {
+		switch pattern[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+			// 字符类开头的 ] 或 ^] 为普通字符
+			if strings.HasPrefix(pattern[j+1:], "]") {
+				j++
+			} else if strings.HasPrefix(pattern[j+1:], "^]") {
+				j += 2
+			}
+		case ']':
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(pattern)
+}
+
+// inlineFlagsPattern 内联标志, 如 (?i) (?im) (?-i)
+var inlineFlagsPattern = regexp.MustCompile(`^\(\?[a-zA-Z-]+\)`)
+
+// inlineFlagsEnd 返回内联标志的结束位置, 不是内联标志时返回0
+func inlineFlagsEnd(pattern string, i int) int {
+	if loc := inlineFlagsPattern.FindStringIndex(pattern[i:]); loc != nil {
+		return i + loc[1]
+	}
+	return 0
+}
+
+// groupPrefixPattern 分组前缀, 如 (?: (?= (?<= (?<name> (?P<name> (?i:
+var groupPrefixPattern = regexp.MustCompile(`^\(\?(?:[:=!>]|<[=!]|P?<[^>]*>|[a-zA-Z-]*:)`)
+
+// groupPrefixEnd 返回分组前缀的结束位置
+func groupPrefixEnd(pattern string, i int) int {
+	if loc := groupPrefixPattern.FindStringIndex(pattern[i:]); loc != nil {
+		return i + loc[1]
+	}
+	return i + 1
+}
+
+// quantifierEnd 解析原子后的量词, 返回量词结束位置、是否无界以及是否为占有量词
+func quantifierEnd(pattern string, i int) (int, bool, bool) {
+	if i >= len(pattern) {
+		return i, false, false
+	}
+
+	end, unbounded := i, false
+	switch pattern[i] {
+	case '*', '+':
+		end, unbounded = i+1, true
+	case '?':
+		end = i + 1
+	case '{':
+		closing := strings.IndexByte(pattern[i:], '}')
+		if closing < 0 {
+			return i, false, false
+		}
+		body := pattern[i+1 : i+closing]
+		lower, upper, hasComma := strings.Cut(body, ",")
+		if _, err := strconv.Atoi(lower); err != nil {
+			return i, false, false
+		}
+		end, unbounded = i+closing+1, hasComma && upper == ""
+	default:
+		return i, false, false
+	}
+
+	possessive := false
+	if end < len(pattern) {
+		switch pattern[end] {
+		case '?':
+			end++
+		case '+':
+			end++
+			possessive = true
+		}
+	}
+	return end, unbounded, possessive
+}
+
+// probeChars 用于判断字符集是否重叠的探测字符
+var probeChars = func() []string {
+	var chars []string
+	for c := 0x20; c < 0x7f; c++ {
+		chars = append(chars, string(rune(c)))
+	}
+	return append(chars, "\t", "\n", "中")
+}()
+
+// atomMatcher 将单字符原子编译为Go正则(忽略大小写), 无法编译时返回nil
+func atomMatcher(atom string) *regexp.Regexp {
+	regex, err := regexp.Compile(`^(?i:` + atom + `)$`)
+	if err != nil {
+		return nil
+	}
+	return regex
+}
+
+// atomsOverlap 判断两个单字符原子是否可以匹配相同字符
+func atomsOverlap(left, right string) bool {
+	leftRegex, rightRegex := atomMatcher(left), atomMatcher(right)
+	if leftRegex == nil || rightRegex == nil {
+		return false
+	}
+	for _, char := range probeChars {
+		if leftRegex.MatchString(char) && rightRegex.MatchString(char) {
+			return true
+		}
+	}
+	return false
+}
+
+// atomProbeChar 返回原子可以匹配的探测字符, 优先选择字母数字
+func atomProbeChar(atom string) string {
+	regex := atomMatcher(atom)
+	if regex == nil {
+		return ""
+	}
+	for _, preferred := range []string{"a", "0", " ", "_"} {
+		if regex.MatchString(preferred) {
+			return preferred
+		}
+	}
+	for _, char := range probeChars {
+		if regex.MatchString(char) {
+			return char
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package ruletest
+
+import (
+	"testing"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestAnalyzeBacktracking 测试回溯风险静态分析
+func TestAnalyzeBacktracking(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []RedosFinding
+	}{
+		{`(a+)+b`, []RedosFinding{{RedosNestedQuantifier, `(a+)+`}}},
+		{`(?:[a-z]+\.)*com`, []RedosFinding{{RedosNestedQuantifier, `(?:[a-z]+\.)*`}}},
+		{`((\w+)@)+`, []RedosFinding{{RedosNestedQuantifier, `((\w+)@)+`}}},
+		{`\w+\d*x`, []RedosFinding{{RedosOverlappingAdjacent, `\w+\d*`}}},
+		{`.*.*=`, []RedosFinding{{RedosOverlappingAdjacent, `.*.*`}}},
+		{`(?i)(a+)+`, []RedosFinding{{RedosNestedQuantifier, `(a+)+`}}},
+		// 无风险的写法
+		{`\w+@\w+\.com`, nil},
+		{`\d+[a-z]+`, nil},
+		{`(a+)?b`, nil},
+		{`(?>a+)+b`, nil},
+		{`(a++)+b`, nil},
+		{`(a{1,10})+b`, nil},
+		{`[(a+)+]`, nil},
+		{`\(a+\)+`, nil},
+		{`(?<name>\d{4})-(?<=x)\d+`, nil},
+	}
+
+	for _, tc := range testCases {
+		findings := AnalyzeBacktracking(tc.pattern)
+		if len(findings) != len(tc.expected) {
+			t.Errorf("AnalyzeBacktracking(%q) = %v, expected %v", tc.pattern, findings, tc.expected)
+			continue
+		}
+		for i := range findings {
+			if findings[i] != tc.expected[i] {
+				t.Errorf("AnalyzeBacktracking(%q)[%d] = %v, expected %v", tc.pattern, i, findings[i], tc.expected[i])
+			}
+		}
+	}
+}
+
+// TestLintRule 测试规则性能检查
+func TestLintRule(t *testing.T) {
+	slow := baserule.Rule{Name: "slow", FRegex: `^(\w+\s?)+$`, Engine: "nfa"}
+	lint, err := LintRule("test: slow", slow, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LintRule failed: %v", err)
+	}
+	if lint.Engine != "java" || len(lint.Findings) == 0 || !lint.GoCompatible {
+		t.Errorf("expected java rule with findings that can use go engine, got %+v", lint)
+	}
+	if !lint.Perf.TimedOut {
+		t.Errorf("expected the rule to exceed the time budget, got %+v", lint.Perf)
+	}
+	if lint.Perf.Error != "" && lint.Perf.Error != "match timeout after 50ms" {
+		t.Errorf("expected the timeout error without the input, got %q", lint.Perf.Error)
+	}
+
+	fast := baserule.Rule{Name: "fast", FRegex: `\d{6}`, SampleCode: "123456"}
+	lint, err = LintRule("test: fast", fast, time.Second)
+	if err != nil {
+		t.Fatalf("LintRule failed: %v", err)
+	}
+	if lint.Engine != "go" || len(lint.Findings) != 0 || lint.GoCompatible || lint.Perf.TimedOut {
+		t.Errorf("expected fast go rule without issues, got %+v", lint)
+	}
+}
@@ -3,52 +3,61 @@ package ruletest
 import (
 	"fmt"
 	"os"
-	"runtime"
+	"sort"
 	"strings"
 	"time"
 )
 
 // genTestReport 生成规则测试报告
-func genTestReport(rulesFile string, totalRules int, compileErrorRules []string, noSampleCodeRules []string, validRules []string) string {
+func genTestReport(summary TestSummary, sampleFailures []RuleSampleFailure, ruleLints []RuleLint, options TestOptions) string {
 	var buf strings.Builder
+	totalRules := summary.Total
+	failedRules := summary.Failed - countSampleOnlyFailures(summary.Results)
 
 	// 报告标题
 	buf.WriteString(fmt.Sprintf("# 规则测试报告\n\n"))
 	buf.WriteString(fmt.Sprintf("## 测试配置\n\n"))
-	buf.WriteString(fmt.Sprintf("- **规则文件**: %s\n", rulesFile))
-	buf.WriteString(fmt.Sprintf("- **测试日期**: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	buf.WriteString(fmt.Sprintf("- **规则文件**: %s\n", summary.RulesFile))
+	buf.WriteString(fmt.Sprintf("- **测试日期**: %s\n", summary.Timestamp))
 	buf.WriteString(fmt.Sprintf("- **总规则数**: %d\n", totalRules))
-	buf.WriteString(fmt.Sprintf("- **Go 版本**: %s\n", runtime.Version()))
+	buf.WriteString(fmt.Sprintf("- **Go 版本**: %s\n", summary.GoVersion))
+	buf.WriteString(fmt.Sprintf("- **耗时预算**: %s\n", options.Budget))
+	buf.WriteString(fmt.Sprintf("- **测试耗时**: %.2fms\n\n", summary.DurationMs))
 
 	// 测试结果摘要
 	buf.WriteString("## 测试结果摘要\n\n")
 	buf.WriteString(fmt.Sprintf("| 状态 | 数量 | 百分比 | 状态码 |\n"))
 	buf.WriteString(fmt.Sprintf("|------|------|--------|--------|\n"))
-	buf.WriteString(fmt.Sprintf("| 有效规则 | %d | %.2f%% | ✅ |\n", len(validRules), float64(len(validRules))/float64(totalRules)*100))
-	buf.WriteString(fmt.Sprintf("| 无 SampleCode | %d | %.2f%% | ⚠️ |\n", len(noSampleCodeRules), float64(len(noSampleCodeRules))/float64(totalRules)*100))
-	buf.WriteString(fmt.Sprintf("| 编译错误 | %d | %.2f%% | ❌ |\n\n", len(compileErrorRules), float64(len(compileErrorRules))/float64(totalRules)*100))
+	buf.WriteString(fmt.Sprintf("| 有效规则 | %d | %.2f%% | ✅ |\n", summary.Passed, percent(summary.Passed, totalRules)))
+	buf.WriteString(fmt.Sprintf("| 无 SampleCode | %d | %.2f%% | ⚠️ |\n", summary.Skipped, percent(summary.Skipped, totalRules)))
+	buf.WriteString(fmt.Sprintf("| 编译错误 | %d | %.2f%% | ❌ |\n", failedRules, percent(failedRules, totalRules)))
+	buf.WriteString(fmt.Sprintf("| 样例断言失败 | %d | - | ❌ |\n\n", len(sampleFailures)))
 
 	// 测试结果分析
 	buf.WriteString("## 测试结果分析\n\n")
-	if len(validRules) == totalRules {
+	if summary.Passed == totalRules {
 		buf.WriteString("### 🎉 测试通过！\n\n")
 		buf.WriteString("所有规则都通过了测试，没有发现任何问题。\n\n")
-	} else if len(validRules) > len(compileErrorRules)+len(noSampleCodeRules) {
+	} else if summary.Failed == 0 {
 		buf.WriteString("### 📊 测试基本通过\n\n")
-		buf.WriteString("大部分规则通过了测试，但仍有一些问题需要解决。\n\n")
+		buf.WriteString("没有失败的规则，但仍有规则缺少 SampleCode。\n\n")
 	} else {
 		buf.WriteString("### ⚠️ 测试未通过\n\n")
-		buf.WriteString("有较多规则未通过测试，需要仔细检查和修复。\n\n")
+		buf.WriteString(fmt.Sprintf("有 %d 个规则未通过测试，需要检查和修复。\n\n", summary.Failed))
 	}
 
 	// 编译错误规则
 	buf.WriteString("## 编译错误规则\n\n")
-	if len(compileErrorRules) > 0 {
-		buf.WriteString(fmt.Sprintf("发现 %d 个规则编译错误:\n\n", len(compileErrorRules)))
-		buf.WriteString("| 规则 | 错误信息 |\n")
-		buf.WriteString("|------|----------|\n")
-		for _, rule := range compileErrorRules {
-			buf.WriteString(fmt.Sprintf("| %s | |\n", rule))
+	if failedRules > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个规则编译错误或 SampleCode 匹配失败:\n\n", failedRules))
+		buf.WriteString("| 规则 | 错误类型 | 错误信息 |\n")
+		buf.WriteString("|------|----------|----------|\n")
+		for _, result := range summary.Results {
+			for _, failure := range result.Failures {
+				if failure.Type != FailureSampleAssert {
+					buf.WriteString(fmt.Sprintf("| %s | %s | %s |\n", result.Identifier(), failure.Type, escapeTableCell(failure.Message)))
+				}
+			}
 		}
 		buf.WriteString("\n")
 		buf.WriteString("### 修复建议\n\n")
@@ -59,14 +68,38 @@ func genTestReport(rulesFile string, totalRules int, compileErrorRules []string,
 		buf.WriteString("未发现编译错误规则。\n\n")
 	}
 
+	// 样例断言失败
+	buf.WriteString("## 样例断言失败\n\n")
+	if len(sampleFailures) > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个样例断言失败:\n\n", len(sampleFailures)))
+		buf.WriteString("| 规则 | 样例 | 期望 | 实际 |\n")
+		buf.WriteString("|------|------|------|------|\n")
+		for _, failure := range sampleFailures {
+			sample := fmt.Sprintf("samples #%d: `%s`", failure.Index, escapeTableCell(failure.Text))
+			expected := escapeTableCell(formatValues(failure.Expected))
+			if failure.Negative {
+				sample = fmt.Sprintf("negative_samples #%d: `%s`", failure.Index, escapeTableCell(failure.Text))
+				expected = "无匹配"
+			} else if len(failure.Expected) == 0 {
+				expected = "至少一个匹配"
+			}
+			buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", failure.Rule, sample, expected, escapeTableCell(formatValues(failure.Actual))))
+		}
+		buf.WriteString("\n")
+	} else {
+		buf.WriteString("所有样例断言均通过。\n\n")
+	}
+
 	// 无 SampleCode 规则
 	buf.WriteString("## 无 SampleCode 规则\n\n")
-	if len(noSampleCodeRules) > 0 {
-		buf.WriteString(fmt.Sprintf("发现 %d 个规则缺少 SampleCode:\n\n", len(noSampleCodeRules)))
+	if summary.Skipped > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个规则缺少 SampleCode:\n\n", summary.Skipped))
 		buf.WriteString("| 规则 |\n")
 		buf.WriteString("|------|\n")
-		for _, rule := range noSampleCodeRules {
-			buf.WriteString(fmt.Sprintf("| %s |\n", rule))
+		for _, result := range summary.Results {
+			if result.Status == TestStatusSkipped {
+				buf.WriteString(fmt.Sprintf("| %s |\n", result.Identifier()))
+			}
 		}
 		buf.WriteString("\n")
 		buf.WriteString("### 修复建议\n\n")
@@ -79,18 +112,26 @@ func genTestReport(rulesFile string, totalRules int, compileErrorRules []string,
 
 	// 有效规则
 	buf.WriteString("## 有效规则\n\n")
-	if len(validRules) > 0 {
-		buf.WriteString(fmt.Sprintf("发现 %d 个有效规则:\n\n", len(validRules)))
-		buf.WriteString("| 规则 |\n")
-		buf.WriteString("|------|\n")
-		for _, rule := range validRules {
-			buf.WriteString(fmt.Sprintf("| %s |\n", rule))
+	if summary.Passed > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个有效规则:\n\n", summary.Passed))
+		buf.WriteString("| 规则 | 引擎 | 测试耗时 |\n")
+		buf.WriteString("|------|------|----------|\n")
+		for _, result := range summary.Results {
+			if result.Status == TestStatusPassed {
+				buf.WriteString(fmt.Sprintf("| %s | %s | %.2fms |\n", result.Identifier(), result.Engine, result.DurationMs))
+			}
 		}
 		buf.WriteString("\n")
 	} else {
 		buf.WriteString("未发现有效规则。\n\n")
 	}
 
+	// 性能检查
+	writeLintReport(&buf, ruleLints, options)
+
+	// 引擎差异
+	writeEngineDiffReport(&buf, summary.Results, options)
+
 	// 测试建议
 	buf.WriteString("## 测试建议\n\n")
 	buf.WriteString("### 下一步操作\n\n")
@@ -108,6 +149,163 @@ func genTestReport(rulesFile string, totalRules int, compileErrorRules []string,
 	return buf.String()
 }
 
+// percent 计算百分比, 总数为0时返回0
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total) * 100
+}
+
+// countSampleOnlyFailures 统计仅因样例断言失败的规则数量
+func countSampleOnlyFailures(results []RuleTestResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Status != TestStatusFailed {
+			continue
+		}
+		sampleOnly := true
+		for _, failure := range result.Failures {
+			if failure.Type != FailureSampleAssert {
+				sampleOnly = false
+				break
+			}
+		}
+		if sampleOnly {
+			count++
+		}
+	}
+	return count
+}
+
+// slowRulesTop 耗时排行展示的规则数量
+const slowRulesTop = 10
+
+// countLintIssues 统计存在回溯风险、可使用 Go 引擎以及超出耗时预算的规则数量
+func countLintIssues(ruleLints []RuleLint) (redosRules, goCompatibleRules, slowRules int) {
+	for _, lint := range ruleLints {
+		if len(lint.Findings) > 0 {
+			redosRules++
+		}
+		if lint.GoCompatible {
+			goCompatibleRules++
+		}
+		if lint.Perf.TimedOut {
+			slowRules++
+		}
+	}
+	return redosRules, goCompatibleRules, slowRules
+}
+
+// writeLintReport 输出性能检查结果: 回溯风险、可使用 Go 引擎的规则、慢规则与耗时排行
+func writeLintReport(buf *strings.Builder, ruleLints []RuleLint, options TestOptions) {
+	redosRules, goCompatibleRules, slowRules := countLintIssues(ruleLints)
+
+	buf.WriteString("## 回溯风险规则\n\n")
+	if redosRules > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个 java 引擎规则存在灾难性回溯风险:\n\n", redosRules))
+		buf.WriteString("| 规则 | 风险类型 | 正则片段 |\n")
+		buf.WriteString("|------|----------|----------|\n")
+		for _, lint := range ruleLints {
+			for _, finding := range lint.Findings {
+				buf.WriteString(fmt.Sprintf("| %s | %s | `%s` |\n", lint.Rule, finding.Kind, escapeTableCell(finding.Fragment)))
+			}
+		}
+		buf.WriteString("\n")
+		buf.WriteString("### 修复建议\n\n")
+		buf.WriteString("1. nested_quantifier: 避免在带有 `*`/`+` 的分组内再使用 `*`/`+`，如将 `(a+)+` 改为 `a+`\n")
+		buf.WriteString("2. overlapping_adjacent: 相邻的无界量词不应匹配相同字符，如将 `\\w+\\w*` 合并为 `\\w+`\n")
+		buf.WriteString("3. 使用占有量词 `*+`/`++` 或原子分组 `(?>...)` 禁止回溯\n\n")
+	} else {
+		buf.WriteString("未发现存在回溯风险的规则。\n\n")
+	}
+
+	buf.WriteString("## 可使用 Go 引擎的规则\n\n")
+	if goCompatibleRules > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个规则被指定为 java 引擎，但可以使用线性时间的 Go 引擎编译，建议设置 `engine: go`:\n\n", goCompatibleRules))
+		buf.WriteString("| 规则 |\n")
+		buf.WriteString("|------|\n")
+		for _, lint := range ruleLints {
+			if lint.GoCompatible {
+				buf.WriteString(fmt.Sprintf("| %s |\n", lint.Rule))
+			}
+		}
+		buf.WriteString("\n")
+	} else {
+		buf.WriteString("未发现可以切换为 Go 引擎的规则。\n\n")
+	}
+
+	buf.WriteString("## 慢规则\n\n")
+	if slowRules > 0 {
+		buf.WriteString(fmt.Sprintf("发现 %d 个规则在对抗输入上超出耗时预算 %s:\n\n", slowRules, options.Budget))
+		buf.WriteString("| 规则 | 引擎 | 耗时 | 对抗输入 | 错误信息 |\n")
+		buf.WriteString("|------|------|------|----------|----------|\n")
+		for _, lint := range ruleLints {
+			if lint.Perf.TimedOut {
+				perf := lint.Perf
+				buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", perf.Rule, perf.Engine, perf.Duration.Round(time.Millisecond), escapeTableCell(perf.Input), escapeTableCell(perf.Error)))
+			}
+		}
+		buf.WriteString("\n")
+	} else {
+		buf.WriteString("所有规则均在耗时预算内完成。\n\n")
+	}
+
+	// 耗时排行
+	sorted := make([]RuleLint, len(ruleLints))
+	copy(sorted, ruleLints)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Perf.Duration > sorted[j].Perf.Duration
+	})
+	if len(sorted) > slowRulesTop {
+		sorted = sorted[:slowRulesTop]
+	}
+	if len(sorted) > 0 {
+		buf.WriteString(fmt.Sprintf("### 耗时最长的 %d 个规则\n\n", len(sorted)))
+		buf.WriteString("| 规则 | 引擎 | 最长耗时 | 对抗输入 |\n")
+		buf.WriteString("|------|------|----------|----------|\n")
+		for _, lint := range sorted {
+			perf := lint.Perf
+			buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", perf.Rule, perf.Engine, perf.Duration.Round(time.Microsecond), escapeTableCell(perf.Input)))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// writeEngineDiffReport 输出 Go 与 Java 引擎匹配结果不一致的规则
+func writeEngineDiffReport(buf *strings.Builder, results []RuleTestResult, options TestOptions) {
+	buf.WriteString("## 引擎差异规则\n\n")
+	diffRules := countEngineDiffRules(results)
+	if diffRules == 0 {
+		buf.WriteString("两种引擎都能编译的规则在样例")
+		if options.CorpusDir != "" {
+			buf.WriteString("与语料")
+		}
+		buf.WriteString("上的匹配结果一致。\n\n")
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("发现 %d 个规则在 Go(RE2) 与 Java(regexp2) 引擎下的提取值不一致，规则在 Burp/HAE 中的结果可能与本工具不同:\n\n", diffRules))
+	buf.WriteString("| 规则 | 使用引擎 | 输入 | 仅 Go 匹配 | 仅 Java 匹配 |\n")
+	buf.WriteString("|------|----------|------|------------|--------------|\n")
+	for _, result := range results {
+		for _, diff := range result.EngineDiffs {
+			buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", diff.Rule, diff.Engine, escapeTableCell(diff.Input),
+				escapeTableCell(formatValues(diff.GoOnly)), escapeTableCell(formatValues(diff.JavaOnly))))
+		}
+	}
+	buf.WriteString("\n")
+	buf.WriteString("### 修复建议\n\n")
+	buf.WriteString("1. 常见差异来源: `\\b`、`\\w`、`\\d` 在 regexp2 中匹配 Unicode 字符，在 RE2 中只匹配 ASCII 字符\n")
+	buf.WriteString("2. 需要与 Burp/HAE 保持一致时，设置 `engine: java`；需要线性时间匹配时，调整正则使两种引擎结果一致后设置 `engine: go`\n\n")
+}
+
+// escapeTableCell 转义 Markdown 表格单元格中的竖线与换行
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
 // getHostname 获取主机名
 func getHostname() string {
 	hostname, err := os.Hostname()
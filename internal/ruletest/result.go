@@ -0,0 +1,117 @@
+package ruletest
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// 规则测试状态
+const (
+	TestStatusPassed  = "passed"  // 通过
+	TestStatusFailed  = "failed"  // 失败(编译错误、样例不匹配或样例断言失败)
+	TestStatusSkipped = "skipped" // 跳过(缺少 sample_code 与 samples)
+)
+
+// 规则测试失败类型
+const (
+	FailureCompileError   = "compile_error"    // 正则编译错误
+	FailureSampleCode     = "sample_code"      // sample_code 匹配失败
+	FailureSampleAssert   = "sample_assertion" // 正负样例断言失败
+	FailureEngineError    = "engine_error"     // 扫描引擎创建失败
+	skippedNoSampleReason = "no sample_code or samples"
+)
+
+// TestFailure 规则测试失败信息
+type TestFailure struct {
+	Type    string `json:"type"`    // 失败类型
+	Message string `json:"message"` // 具体错误
+}
+
+// RuleTestResult 单条规则的测试结果
+type RuleTestResult struct {
+	Group       string        `json:"group"`
+	RuleID      string        `json:"rule_id,omitempty"`
+	RuleName    string        `json:"rule_name"`
+	Engine      string        `json:"engine,omitempty"` // 实际使用的引擎
+	Status      string        `json:"status"`
+	Failures    []TestFailure `json:"failures,omitempty"`
+	Warnings    []string      `json:"warnings,omitempty"`     // 性能检查问题(不影响测试状态)
+	DurationMs  float64       `json:"duration_ms"`            // 规则测试总耗时(毫秒)
+	PerfMs      float64       `json:"perf_ms"`                // 对抗输入的最长耗时(毫秒)
+	SkipReason  string        `json:"skip_reason,omitempty"`  // 跳过原因
+	EngineDiffs []EngineDiff  `json:"engine_diffs,omitempty"` // Go 与 Java 引擎的匹配差异
+
+	identifier string // 规则标识 "group: name"
+}
+
+// Identifier 返回规则标识
+func (r RuleTestResult) Identifier() string {
+	return r.identifier
+}
+
+// addFailure 记录失败信息并将状态置为失败
+func (r *RuleTestResult) addFailure(failureType, message string) {
+	r.Status = TestStatusFailed
+	r.Failures = append(r.Failures, TestFailure{Type: failureType, Message: message})
+}
+
+// TestSummary 规则测试汇总, 用于 JSON 输出
+type TestSummary struct {
+	RulesFile  string           `json:"rules_file"`
+	Timestamp  string           `json:"timestamp"`
+	GoVersion  string           `json:"go_version"`
+	BudgetMs   int64            `json:"budget_ms"`
+	Total      int              `json:"total"`
+	Passed     int              `json:"passed"`
+	Failed     int              `json:"failed"`
+	Skipped    int              `json:"skipped"`
+	DurationMs float64          `json:"duration_ms"`
+	Results    []RuleTestResult `json:"results"`
+}
+
+// newTestSummary 汇总规则测试结果
+func newTestSummary(rulesFile string, results []RuleTestResult, options TestOptions, elapsed time.Duration) TestSummary {
+	summary := TestSummary{
+		RulesFile:  rulesFile,
+		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+		GoVersion:  runtime.Version(),
+		BudgetMs:   options.Budget.Milliseconds(),
+		Total:      len(results),
+		DurationMs: durationMs(elapsed),
+		Results:    results,
+	}
+	for _, result := range results {
+		switch result.Status {
+		case TestStatusPassed:
+			summary.Passed++
+		case TestStatusFailed:
+			summary.Failed++
+		case TestStatusSkipped:
+			summary.Skipped++
+		}
+	}
+	return summary
+}
+
+// SaveJSON 将测试结果保存为 JSON 文件
+func (s TestSummary) SaveJSON(jsonFile string) error {
+	return utils.SaveJSON(jsonFile, s)
+}
+
+// countEngineDiffRules 统计存在引擎差异的规则数量
+func countEngineDiffRules(results []RuleTestResult) int {
+	count := 0
+	for _, result := range results {
+		if len(result.EngineDiffs) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// durationMs 将耗时转换为毫秒
+func durationMs(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
@@ -0,0 +1,84 @@
+package ruletest
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestTestRuleStatus 测试单条规则的测试状态与失败类型
+func TestTestRuleStatus(t *testing.T) {
+	options := TestOptions{Budget: time.Second}
+	testCases := []struct {
+		name     string
+		rule     baserule.Rule
+		status   string
+		failures []string
+	}{
+		{"compile", baserule.Rule{Name: "Bad", FRegex: `abc((`, Engine: "go"}, TestStatusFailed, []string{FailureCompileError}},
+		{"sample_code", baserule.Rule{Name: "Mismatch", FRegex: `token=\d+`, SampleCode: "nope"}, TestStatusFailed, []string{FailureSampleCode}},
+		{"skipped", baserule.Rule{Name: "NoSample", FRegex: `token=\d+`}, TestStatusSkipped, nil},
+		{"assertion", baserule.Rule{Name: "Negative", FRegex: `token=\d+`, SampleCode: "token=123456", NegativeSamples: []string{"token=654321"}}, TestStatusFailed, []string{FailureSampleAssert}},
+		{"passed", baserule.Rule{Name: "Good", FRegex: `token=\d+`, SampleCode: "token=123456"}, TestStatusPassed, nil},
+	}
+
+	for _, tc := range testCases {
+		tc.rule.Loaded = true
+		result, _, _ := testRule("Test Group", tc.rule, options)
+		if result.Status != tc.status {
+			t.Errorf("%s: status = %s, expected %s", tc.name, result.Status, tc.status)
+		}
+		if len(result.Failures) != len(tc.failures) {
+			t.Errorf("%s: failures = %v, expected %v", tc.name, result.Failures, tc.failures)
+			continue
+		}
+		for i, failure := range result.Failures {
+			if failure.Type != tc.failures[i] || failure.Message == "" {
+				t.Errorf("%s: failure %d = %+v, expected type %s with message", tc.name, i, failure, tc.failures[i])
+			}
+		}
+	}
+}
+
+// TestTestSummaryJUnit 测试 JUnit XML 输出
+func TestTestSummaryJUnit(t *testing.T) {
+	results := []RuleTestResult{
+		{Group: "A", RuleName: "Good", Status: TestStatusPassed, DurationMs: 1500},
+		{Group: "A", RuleName: "Bad", Status: TestStatusFailed, Failures: []TestFailure{{FailureCompileError, "missing closing )"}}},
+		{Group: "B", RuleName: "NoSample", Status: TestStatusSkipped, SkipReason: skippedNoSampleReason, Warnings: []string{"slow rule"}},
+	}
+	summary := newTestSummary("rules.yaml", results, TestOptions{Budget: time.Second}, 2*time.Second)
+	if summary.Passed != 1 || summary.Failed != 1 || summary.Skipped != 1 {
+		t.Fatalf("summary counts = %d/%d/%d, expected 1/1/1", summary.Passed, summary.Failed, summary.Skipped)
+	}
+
+	content, err := summary.ToJUnit()
+	if err != nil {
+		t.Fatalf("ToJUnit failed: %v", err)
+	}
+	if !strings.HasPrefix(string(content), xml.Header) {
+		t.Errorf("junit output missing xml header")
+	}
+
+	var root junitTestSuites
+	if err := xml.Unmarshal(content, &root); err != nil {
+		t.Fatalf("invalid junit xml: %v", err)
+	}
+	if root.Tests != 3 || root.Failures != 1 || root.Skipped != 1 || root.Time != "2.000" {
+		t.Errorf("testsuites = %+v", root)
+	}
+	if len(root.Suites) != 2 || root.Suites[0].Name != "A" || root.Suites[0].Tests != 2 || root.Suites[0].Failures != 1 || root.Suites[0].Time != "1.500" {
+		t.Fatalf("testsuite A = %+v", root.Suites)
+	}
+	bad := root.Suites[0].Cases[1]
+	if len(bad.Failures) != 1 || bad.Failures[0].Type != FailureCompileError || bad.Failures[0].Message != "missing closing )" {
+		t.Errorf("failed testcase = %+v", bad)
+	}
+	skipped := root.Suites[1].Cases[0]
+	if skipped.Skipped == nil || skipped.Skipped.Message != skippedNoSampleReason || skipped.SystemOut != "slow rule" {
+		t.Errorf("skipped testcase = %+v", skipped)
+	}
+}
@@ -7,18 +7,43 @@ import (
 	"path/filepath"
 	"privacycheck/internal/baserule"
 	"strings"
+	"time"
 )
 
-// RunRuleTest 测试所有规则并生成测试报告
-func RunRuleTest(rulesFile string, rules []baserule.Rules) {
+// TestOptions 规则测试配置
+type TestOptions struct {
+	Budget    time.Duration // 单条规则在单个对抗输入上的耗时预算
+	JSONFile  string        // JSON 格式测试结果文件(为空则不输出)
+	JUnitFile string        // JUnit XML 格式测试结果文件(为空则不输出)
+	CorpusDir string        // 引擎差异测试使用的语料目录(为空则只使用规则样例)
+
+	corpus []DiffInput // 已加载的语料
+}
+
+// corpusLimitSize 语料文件大小限制(MB)
+const corpusLimitSize = 5
+
+// RunRuleTest 测试所有规则并生成测试报告, 存在失败规则时返回false
+// rulesFile 为第一个加载的规则文件, 测试报告保存在该文件旁边
+func RunRuleTest(rulesFile string, rules []baserule.Rules, options TestOptions) bool {
 	logging.Info("Running rule test mode...")
+	start := time.Now()
+
+	// 加载引擎差异测试语料
+	if options.CorpusDir != "" {
+		corpus, err := LoadCorpus(options.CorpusDir, corpusLimitSize)
+		if err != nil {
+			logging.Fatalf("Failed to load test corpus: %v", err)
+		}
+		options.corpus = corpus
+		logging.Infof("Loaded %d corpus files from %s", len(corpus), options.CorpusDir)
+	}
 
 	// 收集测试结果
 	var (
-		compileErrorRules []string
-		noSampleCodeRules []string
-		validRules        []string
-		totalRules        int
+		results        []RuleTestResult
+		sampleFailures []RuleSampleFailure
+		ruleLints      []RuleLint
 	)
 
 	// 遍历所有规则组和规则
@@ -29,49 +54,145 @@ func RunRuleTest(rulesFile string, rules []baserule.Rules) {
 				continue
 			}
 
-			totalRules++
-			ruleIdentifier := fmt.Sprintf("%s: %s", group.Group, rule.Name)
-
-			// 测试正则表达式编译
-			_, err := baserule.TryCompileWithFallback(rule.FRegex)
-			if err != nil {
-				compileErrorRules = append(compileErrorRules, fmt.Sprintf("%s - 编译错误: %v", ruleIdentifier, err))
-				continue
-			}
-
-			// 检查是否有 SampleCode
-			if rule.SampleCode == "" {
-				noSampleCodeRules = append(noSampleCodeRules, ruleIdentifier)
-				continue
+			ruleStart := time.Now()
+			result, failures, lint := testRule(group.Group, rule, options)
+			result.DurationMs = durationMs(time.Since(ruleStart))
+			results = append(results, result)
+			sampleFailures = append(sampleFailures, failures...)
+			if lint != nil {
+				ruleLints = append(ruleLints, *lint)
 			}
-
-			// 测试正则表达式是否匹配 SampleCode
-			matcher, _ := baserule.TryCompileWithFallback(rule.FRegex)
-			match, err := matcher.MatchString(rule.SampleCode)
-			if err != nil || !match {
-				compileErrorRules = append(compileErrorRules, fmt.Sprintf("%s - SampleCode 匹配失败: %v", ruleIdentifier, err))
-				continue
-			}
-
-			// 规则有效
-			validRules = append(validRules, ruleIdentifier)
 		}
 	}
 
+	summary := newTestSummary(rulesFile, results, options, time.Since(start))
+
 	// 生成测试报告
 	reportFile := fmt.Sprintf("%s_test.md", strings.TrimSuffix(rulesFile, filepath.Ext(rulesFile)))
-	reportContent := genTestReport(rulesFile, totalRules, compileErrorRules, noSampleCodeRules, validRules)
+	reportContent := genTestReport(summary, sampleFailures, ruleLints, options)
 
 	// 保存报告文件
 	if err := os.WriteFile(reportFile, []byte(reportContent), 0644); err != nil {
 		logging.Fatalf("Failed to save test report: %v", err)
 	}
+	if options.JSONFile != "" {
+		if err := summary.SaveJSON(options.JSONFile); err != nil {
+			logging.Fatalf("Failed to save json test result: %v", err)
+		}
+		logging.Infof("JSON test result saved to: %s", options.JSONFile)
+	}
+	if options.JUnitFile != "" {
+		if err := summary.SaveJUnit(options.JUnitFile); err != nil {
+			logging.Fatalf("Failed to save junit test result: %v", err)
+		}
+		logging.Infof("JUnit test result saved to: %s", options.JUnitFile)
+	}
 
 	// 输出测试结果摘要
 	logging.Infof("Rule test completed!")
-	logging.Infof("Total rules tested: %d", totalRules)
-	logging.Infof("Compile error rules: %d", len(compileErrorRules))
-	logging.Infof("No SampleCode rules: %d", len(noSampleCodeRules))
-	logging.Infof("Valid rules: %d", len(validRules))
+	logging.Infof("Total rules tested: %d", summary.Total)
+	for _, result := range results {
+		for _, failure := range result.Failures {
+			logging.Errorf("[%s] %s: %s", result.Identifier(), failure.Type, failure.Message)
+		}
+	}
+	logging.Infof("Failed rules: %d", summary.Failed)
+	logging.Infof("No SampleCode rules: %d", summary.Skipped)
+	logging.Infof("Sample assertion failures: %d", len(sampleFailures))
+	logging.Infof("Valid rules: %d", summary.Passed)
+	redosRules, goCompatibleRules, slowRules := countLintIssues(ruleLints)
+	logging.Infof("Backtracking risk rules: %d", redosRules)
+	logging.Infof("Go compatible java rules: %d", goCompatibleRules)
+	logging.Infof("Slow rules (budget %s): %d", options.Budget, slowRules)
+	logging.Infof("Engine diff rules: %d", countEngineDiffRules(results))
 	logging.Infof("Test report saved to: %s", reportFile)
+
+	return summary.Failed == 0
+}
+
+// testRule 测试单条规则: 正则编译、性能检查、sample_code 匹配与正负样例断言
+func testRule(groupName string, rule baserule.Rule, options TestOptions) (RuleTestResult, []RuleSampleFailure, *RuleLint) {
+	result := RuleTestResult{
+		Group:      groupName,
+		RuleID:     rule.ID,
+		RuleName:   rule.Name,
+		Status:     TestStatusPassed,
+		identifier: fmt.Sprintf("%s: %s", groupName, rule.Name),
+	}
+
+	// 测试正则表达式编译
+	matcher, err := rule.CompileMatcher()
+	if err != nil {
+		result.addFailure(FailureCompileError, err.Error())
+		return result, nil, nil
+	}
+	result.Engine = string(baserule.MatcherEngine(matcher))
+
+	// 性能检查: 回溯风险、引擎选择与对抗输入耗时
+	var lint *RuleLint
+	if ruleLint, err := LintRule(result.identifier, rule, options.Budget); err == nil {
+		lint = &ruleLint
+		result.Warnings = lintWarnings(ruleLint, options.Budget)
+		result.PerfMs = durationMs(ruleLint.Perf.Duration)
+	}
+
+	// 引擎差异: 两种引擎都能编译的规则在样例与语料上的提取值应当一致
+	inputs := append(SampleInputs(rule), options.corpus...)
+	if diffs, err := DiffEngines(result.identifier, rule, inputs); err == nil {
+		result.EngineDiffs = diffs
+		for _, diff := range diffs {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("engine diff on %s", diff))
+		}
+	}
+
+	// 检查是否有 SampleCode 或正样例
+	if rule.SampleCode == "" && len(rule.Samples) == 0 {
+		result.Status = TestStatusSkipped
+		result.SkipReason = skippedNoSampleReason
+		return result, nil, lint
+	}
+
+	// 测试正则表达式是否匹配 SampleCode
+	if rule.SampleCode != "" {
+		match, err := rule.MatchSample(matcher)
+		if err != nil {
+			result.addFailure(FailureSampleCode, err.Error())
+		} else if !match {
+			result.addFailure(FailureSampleCode, fmt.Sprintf("regex does not match sample_code %q", rule.SampleCode))
+		}
+	}
+
+	// 通过扫描引擎执行正负样例断言
+	var sampleFailures []RuleSampleFailure
+	if len(rule.Samples) > 0 || len(rule.NegativeSamples) > 0 {
+		failures, err := CheckSamples(groupName, rule)
+		if err != nil {
+			result.addFailure(FailureEngineError, err.Error())
+		}
+		for _, failure := range failures {
+			result.addFailure(FailureSampleAssert, failure.String())
+			sampleFailures = append(sampleFailures, RuleSampleFailure{Rule: result.identifier, SampleFailure: failure})
+		}
+	}
+
+	return result, sampleFailures, lint
+}
+
+// lintWarnings 将性能检查结果转换为警告信息
+func lintWarnings(lint RuleLint, budget time.Duration) []string {
+	var warnings []string
+	for _, finding := range lint.Findings {
+		warnings = append(warnings, fmt.Sprintf("backtracking risk %s", finding))
+	}
+	if lint.GoCompatible {
+		warnings = append(warnings, "java engine rule can be compiled by the go engine")
+	}
+	if lint.Perf.TimedOut {
+		warning := fmt.Sprintf("slow rule: %s on %s exceeds budget %s", lint.Perf.Duration.Round(time.Millisecond), lint.Perf.Input, budget)
+		if lint.Perf.Error != "" {
+			warning += ": " + lint.Perf.Error
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
 }
@@ -0,0 +1,108 @@
+package ruletest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"privacycheck/internal/baserule"
+	"privacycheck/internal/scanner"
+)
+
+// sampleFilePath 样例测试时使用的文件路径(参与白名单路径匹配)
+const sampleFilePath = "sample.txt"
+
+// SampleFailure 样例断言失败
+type SampleFailure struct {
+	Index    int      // 样例序号(从1开始)
+	Negative bool     // 是否为负样例
+	Text     string   // 样例内容
+	Expected []string // 期望的提取值
+	Actual   []string // 实际的提取值
+}
+
+// RuleSampleFailure 规则的样例断言失败
+type RuleSampleFailure struct {
+	Rule string // 规则标识
+	SampleFailure
+}
+
+// String 返回期望与实际结果的对比描述
+func (f SampleFailure) String() string {
+	if f.Negative {
+		return fmt.Sprintf("negative sample #%d %q: expected no match, actual %s", f.Index, f.Text, formatValues(f.Actual))
+	}
+	if len(f.Expected) == 0 {
+		return fmt.Sprintf("sample #%d %q: expected at least one match, actual none", f.Index, f.Text)
+	}
+	return fmt.Sprintf("sample #%d %q: expected %s, actual %s", f.Index, f.Text, formatValues(f.Expected), formatValues(f.Actual))
+}
+
+// formatValues 格式化提取值列表
+func formatValues(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// CheckSamples 按扫描时的方式(忽略大小写、多行模式、提取分组、熵检测、校验器与白名单)执行规则的正负样例
+// 正样例需要产生结果, 配置了 expect 时提取值需与期望一致(不区分顺序); 负样例不能产生任何结果
+// 规则正则直接编译后逐条执行, 不为每条规则创建扫描引擎
+func CheckSamples(group string, rule baserule.Rule) ([]SampleFailure, error) {
+	matcher, err := rule.CompileMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []SampleFailure
+	for i, sample := range rule.Samples {
+		actual, err := extractValues(group, rule, matcher, sample.Text)
+		if err != nil {
+			return failures, err
+		}
+		if !sampleMatched(sample.Expect, actual) {
+			failures = append(failures, SampleFailure{Index: i + 1, Text: sample.Text, Expected: sample.Expect, Actual: actual})
+		}
+	}
+
+	for i, sample := range rule.NegativeSamples {
+		actual, err := extractValues(group, rule, matcher, sample)
+		if err != nil {
+			return failures, err
+		}
+		if len(actual) > 0 {
+			failures = append(failures, SampleFailure{Index: i + 1, Negative: true, Text: sample, Actual: actual})
+		}
+	}
+
+	return failures, nil
+}
+
+// extractValues 返回样例中扫描得到的提取值(不包括规则分析器的分析结果)
+func extractValues(group string, rule baserule.Rule, matcher baserule.RegexMatcher, text string) ([]string, error) {
+	results, err := scanner.ApplyRule(group, rule, matcher, text, sampleFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, result := range results {
+		if result.Analysis != "" {
+			continue
+		}
+		values = append(values, result.Match)
+	}
+	return values, nil
+}
+
+// sampleMatched 判断实际提取值是否满足期望
+func sampleMatched(expected, actual []string) bool {
+	if len(expected) == 0 {
+		return len(actual) > 0
+	}
+	sortedExpected, sortedActual := slices.Clone(expected), slices.Clone(actual)
+	slices.Sort(sortedExpected)
+	slices.Sort(sortedActual)
+	return slices.Equal(sortedExpected, sortedActual)
+}
@@ -0,0 +1,66 @@
+package ruletest
+
+import (
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestCheckSamples 测试正负样例断言
+func TestCheckSamples(t *testing.T) {
+	rule := baserule.Rule{
+		Name:        "Token",
+		FRegex:      `token=([a-z0-9]{8})`,
+		SecretGroup: 1,
+		Engine:      "go",
+		Loaded:      true,
+		Samples: []baserule.RuleSample{
+			{Text: "token=abcd1234 TOKEN=ffff0000", Expect: []string{"ffff0000", "abcd1234"}},
+			{Text: "token=12345678"},
+			{Text: "token=abcd1234", Expect: []string{"00000000"}},
+			{Text: "nothing here"},
+		},
+		NegativeSamples: []string{"token=abc", "token=deadbeef"},
+	}
+
+	failures, err := CheckSamples("Test Group", rule)
+	if err != nil {
+		t.Fatalf("CheckSamples failed: %v", err)
+	}
+
+	expected := []SampleFailure{
+		{Index: 3, Text: "token=abcd1234", Expected: []string{"00000000"}, Actual: []string{"abcd1234"}},
+		{Index: 4, Text: "nothing here"},
+		{Index: 2, Negative: true, Text: "token=deadbeef", Actual: []string{"deadbeef"}},
+	}
+	if len(failures) != len(expected) {
+		t.Fatalf("CheckSamples() = %v, expected %d failures", failures, len(expected))
+	}
+	for i, failure := range failures {
+		if failure.String() != expected[i].String() {
+			t.Errorf("failure %d = %q, expected %q", i, failure, expected[i])
+		}
+	}
+}
+
+// TestCheckSamplesValidator 测试样例断言经过校验器过滤
+func TestCheckSamplesValidator(t *testing.T) {
+	rule := baserule.Rule{
+		Name:            "IDCard",
+		FRegex:          `\b(\d{17}[\dXx])\b`,
+		SecretGroup:     1,
+		Engine:          "go",
+		Validator:       "idcard",
+		Loaded:          true,
+		Samples:         []baserule.RuleSample{{Text: "ID: 110101199001011237", Expect: []string{"110101199001011237"}}},
+		NegativeSamples: []string{"ID: 110101199001011234"},
+	}
+
+	failures, err := CheckSamples("Test Group", rule)
+	if err != nil {
+		t.Fatalf("CheckSamples failed: %v", err)
+	}
+	if len(failures) != 0 {
+		t.Errorf("CheckSamples() = %v, expected no failures", failures)
+	}
+}
@@ -0,0 +1,77 @@
+package scanner
+
+// ahoCorasick 多模式字符串匹配自动机(按字节构建完整转移表), 一次遍历找出内容中出现的全部关键字
+type ahoCorasick struct {
+	delta   [][256]int32 // 状态转移表
+	outputs [][]int32    // 到达状态时匹配到的关键字序号(包含失败链上的输出)
+	count   int          // 关键字数量
+}
+
+// newAhoCorasick 根据关键字构建自动机
+func newAhoCorasick(keywords []string) *ahoCorasick {
+	ac := &ahoCorasick{count: len(keywords)}
+	ac.addState()
+
+	// 构建字典树, 0 表示尚未建立转移(根状态除外)
+	for index, keyword := range keywords {
+		state := int32(0)
+		for i := 0; i < len(keyword); i++ {
+			next := ac.delta[state][keyword[i]]
+			if next == 0 {
+				next = ac.addState()
+				ac.delta[state][keyword[i]] = next
+			}
+			state = next
+		}
+		ac.outputs[state] = append(ac.outputs[state], int32(index))
+	}
+
+	// 按广度优先计算失败链接并补全转移表
+	fail := make([]int32, len(ac.delta))
+	var queue []int32
+	for b := 0; b < 256; b++ {
+		if next := ac.delta[0][b]; next != 0 {
+			queue = append(queue, next)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		ac.outputs[state] = append(ac.outputs[state], ac.outputs[fail[state]]...)
+		for b := 0; b < 256; b++ {
+			next := ac.delta[state][b]
+			if next == 0 {
+				ac.delta[state][b] = ac.delta[fail[state]][b]
+				continue
+			}
+			fail[next] = ac.delta[fail[state]][b]
+			queue = append(queue, next)
+		}
+	}
+
+	return ac
+}
+
+// addState 添加新状态并返回状态序号
+func (ac *ahoCorasick) addState() int32 {
+	ac.delta = append(ac.delta, [256]int32{})
+	ac.outputs = append(ac.outputs, nil)
+	return int32(len(ac.delta) - 1)
+}
+
+// match 返回每个关键字是否出现在内容中, 全部关键字都出现后提前结束
+func (ac *ahoCorasick) match(content string) []bool {
+	hits := make([]bool, ac.count)
+	remaining := ac.count
+	state := int32(0)
+	for i := 0; i < len(content) && remaining > 0; i++ {
+		state = ac.delta[state][content[i]]
+		for _, index := range ac.outputs[state] {
+			if !hits[index] {
+				hits[index] = true
+				remaining--
+			}
+		}
+	}
+	return hits
+}
@@ -0,0 +1,271 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+	"privacycheck/internal/baserule"
+)
+
+// cacheVersion 缓存文件格式版本, 结果结构或扫描行为变化时递增, 旧版本缓存整体失效
+const cacheVersion = 8
+
+// cacheEntry 单个文件的缓存结果
+type cacheEntry struct {
+	Size    int64        `json:"size"`
+	ModTime int64        `json:"mod_time"` // 修改时间(纳秒时间戳)
+	Hash    string       `json:"hash"`     // 扫描内容 SHA-256
+	Results []ScanResult `json:"results"`
+	Rules   []resultRule `json:"rules"` // 结果对应的规则key与优先级(结果中不输出)
+}
+
+// fileState 扫描时的文件状态
+// 大小与修改时间在读取文件前获取, 哈希为实际扫描内容的 SHA-256; 扫描期间文件被修改时,
+// 记录的修改时间与哈希都和新内容不一致, 之后的扫描不会把旧内容的结果当作新内容的结果
+type fileState struct {
+	Size    int64
+	ModTime int64 // 修改时间(纳秒时间戳)
+	Hash    string
+}
+
+// statFile 获取文件的大小与修改时间, 哈希在读取内容时计算
+func statFile(filePath string) (fileState, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{Size: info.Size(), ModTime: info.ModTime().UnixNano()}, nil
+}
+
+// cacheFile 缓存文件内容
+type cacheFile struct {
+	Version     int                    `json:"version"`
+	Fingerprint string                 `json:"fingerprint"` // 生效规则集与影响结果的扫描配置的指纹
+	Entries     map[string]*cacheEntry `json:"entries"`     // 文件路径 -> 缓存结果
+}
+
+// ResultCache 按文件内容哈希与规则指纹缓存扫描结果, 扫描结束后持久化
+// 文件大小与修改时间未变化时直接命中; 否则计算内容哈希, 与同路径或其他路径的相同内容比较
+// 内容哈希为读取并解码后的扫描内容的 SHA-256, 与扫描使用同一份内容
+type ResultCache struct {
+	path        string
+	fingerprint string
+
+	loaded map[string]*cacheEntry // 从缓存文件加载的缓存(扫描期间只读)
+	hashes map[string]string      // 内容哈希 -> 加载的缓存中具有该内容的文件路径(扫描期间只读)
+
+	pathAllowlist bool // 规则配置了路径白名单, 结果依赖文件路径
+	keyFiles      bool // 启用了密钥文件检测, 结果依赖文件扩展名
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry // 本次扫描使用的缓存(仅保留本次扫描的文件)
+
+	hits     atomic.Int64 // 大小与修改时间未变化的命中次数
+	hashHits atomic.Int64 // 通过内容哈希的命中次数
+	misses   atomic.Int64 // 未命中次数
+}
+
+// RulesFingerprint 计算规则集与影响结果的扫描配置的指纹
+// 任一规则字段、分块阈值、解码配置、弱密钥字典或密钥文件检测开关变化都会改变指纹
+func RulesFingerprint(rules baserule.RuleMap, config *ScanConfig) (string, error) {
+	data, err := json.Marshal(struct {
+		Version    int              `json:"version"`
+		ChunkLimit int              `json:"chunk_limit"`
+		Decode     *DecodeOptions   `json:"decode"`
+		Wordlist   []string         `json:"wordlist"`
+		KeyFiles   bool             `json:"key_files"`
+		Rules      baserule.RuleMap `json:"rules"`
+	}{cacheVersion, config.ChunkLimit, config.Decode, config.JWTWordlist, config.KeyFiles, rules})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// NewResultCache 加载缓存文件, 文件不存在、无法解析或指纹不一致时从空缓存开始
+func NewResultCache(path, fingerprint string) *ResultCache {
+	cache := &ResultCache{
+		path:        path,
+		fingerprint: fingerprint,
+		entries:     make(map[string]*cacheEntry),
+		loaded:      make(map[string]*cacheEntry),
+		hashes:      make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logging.Warnf("failed to read scan cache %s: %v", path, err)
+		}
+		return cache
+	}
+	var stored cacheFile
+	if err := json.Unmarshal(data, &stored); err != nil {
+		logging.Warnf("failed to parse scan cache %s, starting with empty cache: %v", path, err)
+		return cache
+	}
+	if stored.Version != cacheVersion || stored.Fingerprint != fingerprint {
+		logging.Infof("scan cache %s was created with different rules, discarding %d entries", path, len(stored.Entries))
+		return cache
+	}
+
+	for filePath, entry := range stored.Entries {
+		if entry == nil {
+			continue
+		}
+		restoreResultRules(entry.Results, entry.Rules)
+		cache.loaded[filePath] = entry
+		cache.hashes[entry.Hash] = filePath
+	}
+	logging.Infof("loaded %d entries from scan cache %s", len(cache.loaded), path)
+	return cache
+}
+
+// setPathDependence 根据规则的路径白名单与密钥文件检测开关, 限制其他路径的相同内容的结果复用
+func (c *ResultCache) setPathDependence(rules baserule.RuleMap, keyFiles bool) {
+	c.keyFiles = keyFiles
+	for _, ruleList := range rules {
+		for _, rule := range ruleList {
+			if rule.Loaded && len(rule.Allowlist.Paths) > 0 {
+				c.pathAllowlist = true
+				return
+			}
+		}
+	}
+}
+
+// shareable 判断其他路径的相同内容的缓存结果是否可以用于当前文件
+// 规则配置了路径白名单时结果依赖路径, 不复用; 启用密钥文件检测时只在扩展名类型相同的文件之间复用
+func (c *ResultCache) shareable(source, filePath string) bool {
+	if source == filePath {
+		return true
+	}
+	if c.pathAllowlist {
+		return false
+	}
+	return !c.keyFiles || isKeyFileExtension(source) == isKeyFileExtension(filePath)
+}
+
+// Get 返回文件的缓存结果及缓存内容的哈希, state 为读取文件前获取的文件状态, hash 按需计算扫描内容的哈希
+// 大小与修改时间一致时直接命中, 否则按内容哈希查找同路径或其他路径的相同内容(结果中的文件路径替换为当前路径)
+// 结果依赖文件路径时(见 shareable)不复用其他路径的结果, 重新扫描
+func (c *ResultCache) Get(filePath string, state fileState, hash func() (string, error)) ([]ScanResult, string, bool) {
+	entry, ok := c.loaded[filePath]
+	if ok && entry.Size == state.Size && entry.ModTime == state.ModTime {
+		c.store(filePath, entry)
+		c.hits.Add(1)
+		return entry.Results, entry.Hash, true
+	}
+
+	if len(c.loaded) == 0 {
+		c.misses.Add(1)
+		return nil, "", false
+	}
+	contentHash, err := hash()
+	if err != nil {
+		c.misses.Add(1)
+		return nil, "", false
+	}
+
+	source, ok := c.hashes[contentHash]
+	if entry != nil && entry.Hash == contentHash {
+		source, ok = filePath, true
+	}
+	if !ok || !c.shareable(source, filePath) {
+		c.misses.Add(1)
+		return nil, "", false
+	}
+	cached := c.loaded[source]
+
+	results := cached.Results
+	if source != filePath {
+		results = make([]ScanResult, len(cached.Results))
+		for i, result := range cached.Results {
+			result.File = filePath
+			results[i] = result
+		}
+	}
+	c.store(filePath, &cacheEntry{Size: state.Size, ModTime: state.ModTime, Hash: contentHash, Results: results, Rules: cached.Rules})
+	c.hashHits.Add(1)
+	return results, contentHash, true
+}
+
+// Set 缓存文件的扫描结果, state 为读取文件前获取的大小、修改时间与扫描内容的哈希
+// 分析器的分析结果依赖当前时间(如过期时间), 只缓存正则匹配结果, 读取缓存后重新分析;
+// 二进制密钥文件的分析结果来自文件内容而不是匹配值, 整个文件不缓存
+func (c *ResultCache) Set(filePath string, state fileState, results []ScanResult) {
+	matched := make([]ScanResult, 0, len(results))
+	for _, result := range results {
+		if result.Group == KeyFileGroup {
+			return
+		}
+		if result.Analysis == "" {
+			matched = append(matched, result)
+		}
+	}
+	c.store(filePath, &cacheEntry{Size: state.Size, ModTime: state.ModTime, Hash: state.Hash, Results: matched, Rules: resultRules(matched)})
+}
+
+// store 记录本次扫描使用的缓存
+func (c *ResultCache) store(filePath string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[filePath] = entry
+}
+
+// Save 保存缓存文件, 只保留本次扫描过的文件
+// 缓存包含匹配到的敏感信息, 与扫描日志一样以仅所有者可读写的权限写入临时文件后替换
+func (c *ResultCache) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cacheFile{Version: cacheVersion, Fingerprint: c.fingerprint, Entries: c.entries}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := utils.EnsureDir(c.path, true); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	temp := c.path + ".tmp"
+	file, err := os.OpenFile(temp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create scan cache %s: %w", c.path, err)
+	}
+	// 已存在的临时文件保留原有权限, 需要重新设置
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set scan cache permissions %s: %w", c.path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write scan cache %s: %w", c.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write scan cache %s: %w", c.path, err)
+	}
+	if err := os.Rename(temp, c.path); err != nil {
+		return fmt.Errorf("failed to replace scan cache %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// LogStats 输出缓存命中统计
+func (c *ResultCache) LogStats() {
+	hits, hashHits, misses := c.hits.Load(), c.hashHits.Load(), c.misses.Load()
+	total := hits + hashHits + misses
+	rate := 0.0
+	if total > 0 {
+		rate = float64(hits+hashHits) / float64(total) * 100
+	}
+	logging.Infof("scan cache: %d hits (%d unchanged, %d by content hash), %d misses, hit rate %.1f%%", hits+hashHits, hits, hashHits, misses, rate)
+}
@@ -0,0 +1,335 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestResultCache 测试缓存的持久化、修改检测与按内容哈希复用
+func TestResultCache(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "scan.cache")
+	fileA := filepath.Join(dir, "a.txt")
+	fileB := filepath.Join(dir, "b.txt")
+	writeFile(t, fileA, "password = hunter22")
+
+	cache := NewResultCache(cachePath, "v1")
+	if _, ok := getCached(t, cache, fileA); ok {
+		t.Fatalf("expected miss on empty cache")
+	}
+	cache.Set(fileA, scannedState(t, fileA), []ScanResult{{File: fileA, RuleName: "Password", Match: "hunter22"}})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// 未修改的文件直接命中
+	cache = NewResultCache(cachePath, "v1")
+	results, ok := getCached(t, cache, fileA)
+	if !ok || len(results) != 1 || results[0].Match != "hunter22" {
+		t.Fatalf("expected cached results for unchanged file, got %v %v", results, ok)
+	}
+
+	// 修改时间变化但内容相同时通过内容哈希命中; 相同内容的其他文件复用结果并替换文件路径
+	touch := time.Now().Add(time.Hour)
+	if err := os.Chtimes(fileA, touch, touch); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	writeFile(t, fileB, "password = hunter22")
+	if _, ok := getCached(t, cache, fileA); !ok {
+		t.Errorf("expected content hash hit for touched file")
+	}
+	results, ok = getCached(t, cache, fileB)
+	if !ok || len(results) != 1 || results[0].File != fileB {
+		t.Errorf("expected results copied to %s, got %v %v", fileB, results, ok)
+	}
+	if cache.hits.Load() != 1 || cache.hashHits.Load() != 2 || cache.misses.Load() != 0 {
+		t.Errorf("unexpected stats: hits=%d hashHits=%d misses=%d", cache.hits.Load(), cache.hashHits.Load(), cache.misses.Load())
+	}
+
+	// 内容变化时未命中
+	writeFile(t, fileA, "password = changed1")
+	if _, ok := getCached(t, cache, fileA); ok {
+		t.Errorf("expected miss for modified file")
+	}
+
+	// 规则指纹变化时缓存整体失效
+	if cache := NewResultCache(cachePath, "v2"); len(cache.loaded) != 0 {
+		t.Errorf("expected cache discarded for different fingerprint, got %d entries", len(cache.loaded))
+	}
+}
+
+// TestResultCacheModifiedDuringScan 测试扫描期间被修改的文件, 缓存的旧内容结果不会被当作新内容的结果
+func TestResultCacheModifiedDuringScan(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "scan.cache")
+	file := filepath.Join(dir, "a.txt")
+	writeFile(t, file, "password = hunter22")
+
+	// 读取前获取状态并扫描旧内容, 缓存结果前文件已被修改
+	state := scannedState(t, file)
+	writeFile(t, file, "password = changed1")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	cache := NewResultCache(cachePath, "v1")
+	cache.Set(file, state, []ScanResult{{File: file, RuleName: "Password", Match: "hunter22"}})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cache = NewResultCache(cachePath, "v1")
+	if results, ok := getCached(t, cache, file); ok {
+		t.Errorf("expected miss for file modified during the scan, got %v", results)
+	}
+}
+
+// TestRulesFingerprint 测试规则指纹随规则与影响结果的扫描配置变化
+func TestRulesFingerprint(t *testing.T) {
+	rules := baserule.RuleMap{"Group": []baserule.Rule{{Name: "Token", FRegex: `token=\w+`, Loaded: true}}}
+	base, err := RulesFingerprint(rules, &ScanConfig{ChunkLimit: 5})
+	if err != nil {
+		t.Fatalf("RulesFingerprint failed: %v", err)
+	}
+	if same, _ := RulesFingerprint(rules, &ScanConfig{ChunkLimit: 5, Workers: 4}); same != base {
+		t.Errorf("expected stable fingerprint")
+	}
+	for name, config := range map[string]*ScanConfig{
+		"chunk limit":       {ChunkLimit: 0},
+		"decode options":    {ChunkLimit: 5, Decode: &DecodeOptions{Depth: 2}},
+		"analyzer wordlist": {ChunkLimit: 5, JWTWordlist: []string{"hunter2"}},
+		"key files":         {ChunkLimit: 5, KeyFiles: true},
+	} {
+		if other, _ := RulesFingerprint(rules, config); other == base {
+			t.Errorf("expected fingerprint to change with %s", name)
+		}
+	}
+	changed := baserule.RuleMap{"Group": []baserule.Rule{{Name: "Token", FRegex: `token=\w{8,}`, Loaded: true}}}
+	if other, _ := RulesFingerprint(changed, &ScanConfig{ChunkLimit: 5}); other == base {
+		t.Errorf("expected fingerprint to change with rule regex")
+	}
+}
+
+// TestResultCachePathAllowlist 测试规则配置路径白名单时, 相同内容的其他文件不复用缓存结果
+func TestResultCachePathAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "vendor"), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	vendorFile := filepath.Join(dir, "vendor", "a.txt")
+	srcFile := filepath.Join(dir, "src.txt")
+	writeFile(t, vendorFile, "token=abcdef123456")
+	writeFile(t, srcFile, "token=abcdef123456")
+
+	rules := baserule.RuleMap{"Group": []baserule.Rule{{
+		Name:      "Token",
+		FRegex:    `token=\w+`,
+		Loaded:    true,
+		Allowlist: baserule.Allowlist{Paths: []string{`vendor/`}},
+	}}}
+	config := &ScanConfig{ProjectPath: dir, Workers: 1, CacheFile: filepath.Join(dir, "scan.cache")}
+
+	scanner, err := NewScanner(rules, config)
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+	if results, err := scanner.Scan([]string{vendorFile}); err != nil || len(results) != 0 {
+		t.Fatalf("expected vendor file to be allowlisted, got %v %v", results, err)
+	}
+
+	scanner, err = NewScanner(rules, config)
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+	results, err := scanner.Scan([]string{srcFile})
+	if err != nil || len(results) != 1 || results[0].File != srcFile {
+		t.Errorf("expected src file to be rescanned with 1 result, got %v %v", results, err)
+	}
+	if scanner.cache.hashHits.Load() != 0 || scanner.cache.misses.Load() != 1 {
+		t.Errorf("expected a cache miss, got hashHits=%d misses=%d", scanner.cache.hashHits.Load(), scanner.cache.misses.Load())
+	}
+
+	// 启用密钥文件检测时只在扩展名类型相同的文件之间复用
+	cache := &ResultCache{keyFiles: true}
+	if cache.shareable("a.der", "b.txt") || !cache.shareable("a.der", "b.CRT") || !cache.shareable("a.txt", "b.go") {
+		t.Errorf("unexpected key file extension sharing")
+	}
+}
+
+// TestResultCacheAnalysis 测试分析结果不写入缓存, 读取缓存时按当前时间重新分析
+func TestResultCacheAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	expiry := time.Now().Add(time.Hour)
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiry.Unix()))) + ".c2lnbmF0dXJl"
+	file := filepath.Join(dir, "token.txt")
+	writeFile(t, file, "token: "+token)
+
+	rules := baserule.RuleMap{"Keys": []baserule.Rule{{
+		Name:     "JSON Web Token",
+		FRegex:   `(eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*)`,
+		Engine:   "go",
+		Analyzer: baserule.AnalyzerJWT,
+		Loaded:   true,
+	}}}
+	config := &ScanConfig{ProjectPath: dir, Workers: 1, CacheFile: filepath.Join(dir, "scan.cache")}
+
+	// 在指定时间扫描, 返回 jwt.expiry 分析结果的严重等级
+	scan := func(now time.Time) (*Scanner, string) {
+		instance, err := NewScanner(rules, config)
+		if err != nil {
+			t.Fatalf("NewScanner failed: %v", err)
+		}
+		instance.engine.analyzer.Now = now
+		results, err := instance.Scan([]string{file})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, result := range results {
+			if result.Analysis == "jwt.expiry" {
+				return instance, result.Severity
+			}
+		}
+		t.Fatalf("expected jwt.expiry result, got %+v", results)
+		return nil, ""
+	}
+
+	if _, severity := scan(time.Now()); severity != baserule.SeverityHigh {
+		t.Errorf("expected unexpired token to be high, got %s", severity)
+	}
+	instance, severity := scan(expiry.Add(time.Hour))
+	if instance.cache.hits.Load() != 1 || severity != baserule.SeverityLow {
+		t.Errorf("expected cached token to be analyzed again as expired, got hits=%d severity=%s", instance.cache.hits.Load(), severity)
+	}
+	if cached := instance.cache.entries[file].Results; len(cached) != 1 || cached[0].Analysis != "" {
+		t.Errorf("expected only the regex match to be cached, got %+v", cached)
+	}
+
+	// 二进制密钥文件的结果整体不缓存
+	cache := NewResultCache(filepath.Join(dir, "other.cache"), "v1")
+	cache.Set(file, scannedState(t, file), []ScanResult{{File: file, Group: KeyFileGroup, RuleName: KeyFileRuleName, Match: "token.txt"}})
+	if _, ok := cache.entries[file]; ok {
+		t.Errorf("expected key file results not to be cached")
+	}
+}
+
+// TestResultCacheAnalysisSameName 测试命中缓存时按规则key找到产生匹配的规则, 同名规则中只有配置了分析器的规则的匹配被分析
+func TestResultCacheAnalysisSameName(t *testing.T) {
+	dir := t.TempDir()
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "."
+	file := filepath.Join(dir, "token.txt")
+	writeFile(t, file, "id: tok_0123456789\nauth: Bearer "+token+"\n")
+
+	// 提取分组的值不能再次匹配规则自身的正则, 无法通过重新匹配区分同名规则
+	rules := baserule.RuleMap{"Keys": []baserule.Rule{
+		{Name: "Token", FRegex: `tok_[a-z0-9]{10}`, Engine: "go", Loaded: true},
+		{Name: "Token", FRegex: `Bearer\s+(eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*)`, SecretGroup: 1, Engine: "go", Analyzer: baserule.AnalyzerJWT, Loaded: true},
+	}}
+	config := &ScanConfig{ProjectPath: dir, Workers: 1, CacheFile: filepath.Join(dir, "scan.cache")}
+
+	// 扫描文件, 返回各规则key的分析结果类型
+	scan := func() (*Scanner, map[string][]string) {
+		instance, err := NewScanner(rules, config)
+		if err != nil {
+			t.Fatalf("NewScanner failed: %v", err)
+		}
+		results, err := instance.Scan([]string{file})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		analyzed := make(map[string][]string)
+		for _, result := range results {
+			if result.Analysis != "" {
+				analyzed[result.RuleKey] = append(analyzed[result.RuleKey], result.Analysis)
+			}
+		}
+		return instance, analyzed
+	}
+
+	_, fresh := scan()
+	if len(fresh) != 1 || len(fresh["Keys_1"]) == 0 {
+		t.Fatalf("expected analysis results of the JWT rule only, got %v", fresh)
+	}
+	instance, cached := scan()
+	if instance.cache.hits.Load() != 1 {
+		t.Fatalf("expected cache hit, got %d hits", instance.cache.hits.Load())
+	}
+	if fmt.Sprint(cached) != fmt.Sprint(fresh) {
+		t.Errorf("expected cached results to be analyzed like a fresh scan, got %v want %v", cached, fresh)
+	}
+}
+
+// TestResultCacheSavePermissions 测试缓存文件以仅所有者可读写的权限保存, 替换已存在的缓存文件
+func TestResultCacheSavePermissions(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "cache", "scan.cache")
+	file := filepath.Join(dir, "a.txt")
+	writeFile(t, file, "password = hunter22")
+
+	for i := 0; i < 2; i++ {
+		cache := NewResultCache(cachePath, "v1")
+		cache.Set(file, scannedState(t, file), []ScanResult{{File: file, Match: "hunter22"}})
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		info, err := os.Stat(cachePath)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("expected cache permissions 0600, got %o", perm)
+		}
+		if _, err := os.Stat(cachePath + ".tmp"); err == nil {
+			t.Errorf("expected temporary cache file to be renamed")
+		}
+		// 之前版本以默认权限创建的缓存文件被替换后同样只有所有者可读写
+		if err := os.Chmod(cachePath, 0644); err != nil {
+			t.Fatalf("Chmod failed: %v", err)
+		}
+	}
+	if results, ok := getCached(t, NewResultCache(cachePath, "v1"), file); !ok || len(results) != 1 {
+		t.Errorf("expected saved cache to be loaded, got %v %v", results, ok)
+	}
+}
+
+// scannedState 返回文件的大小、修改时间与扫描内容的哈希
+func scannedState(t *testing.T, path string) fileState {
+	t.Helper()
+	state, err := statFile(path)
+	if err != nil {
+		t.Fatalf("statFile failed: %v", err)
+	}
+	reader := &fileReader{filePath: path}
+	if state.Hash, err = reader.hash(); err != nil {
+		t.Fatalf("hash failed: %v", err)
+	}
+	return state
+}
+
+// getCached 按扫描时的方式查找文件的缓存结果
+func getCached(t *testing.T, cache *ResultCache, path string) ([]ScanResult, bool) {
+	t.Helper()
+	state, err := statFile(path)
+	if err != nil {
+		t.Fatalf("statFile failed: %v", err)
+	}
+	reader := &fileReader{filePath: path}
+	results, _, ok := cache.Get(path, state, reader.hash)
+	return results, ok
+}
+
+// writeFile 写入测试文件
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
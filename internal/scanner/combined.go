@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"privacycheck/internal/baserule"
+)
+
+// combinedBatchSize 每个合并正则包含的最大规则数量
+// 批次越小, 无匹配时可以跳过的规则粒度越细; 批次越大, 遍历内容的次数越少
+const combinedBatchSize = 16
+
+// combinedBatch 由多个 Go 引擎规则合并而成的正则
+// 合并正则一次遍历内容, 找出有匹配的规则及匹配区间, 候选规则再使用各自的正则精确提取
+type combinedBatch struct {
+	regex    *regexp.Regexp
+	keys     []string         // 每个分支对应的规则key
+	groups   []int            // 每个分支外层捕获组的序号
+	anchored []*regexp.Regexp // 每个分支规则锚定在开头的正则, 用于检查内容开头被其他分支遮挡的匹配
+	leading  []*regexp.Regexp // 每个分支规则前接一个字符并锚定的正则, 保留左侧字符供 \b、\B、^ 判断
+}
+
+// combinedMatcher 合并正则预过滤器
+type combinedMatcher struct {
+	batches []*combinedBatch
+	members map[string]bool // 参与合并的规则key
+}
+
+// combinable 判断规则是否可以参与合并: 只合并使用 Go 引擎的规则
+func combinable(matcher baserule.RegexMatcher) bool {
+	return baserule.MatcherEngine(matcher) == baserule.RegexEngineGo
+}
+
+// newCombinedMatcher 将可以合并的规则按批次合并为正则, 合并失败的批次不参与预过滤
+func newCombinedMatcher(keys []string, rules map[string]baserule.Rule, matchers map[string]baserule.RegexMatcher) *combinedMatcher {
+	combined := &combinedMatcher{members: make(map[string]bool)}
+
+	var pending []string
+	flush := func() {
+		if len(pending) > 1 {
+			if batch, err := newCombinedBatch(pending, rules); err == nil {
+				combined.batches = append(combined.batches, batch)
+				for _, key := range pending {
+					combined.members[key] = true
+				}
+			}
+		}
+		pending = nil
+	}
+
+	for _, key := range keys {
+		if !combinable(matchers[key]) {
+			continue
+		}
+		pending = append(pending, key)
+		if len(pending) == combinedBatchSize {
+			flush()
+		}
+	}
+	flush()
+
+	if len(combined.batches) == 0 {
+		return nil
+	}
+	return combined
+}
+
+// newCombinedBatch 合并一批规则: (?m)(?i)(?:(规则1)|(规则2)|...)
+func newCombinedBatch(keys []string, rules map[string]baserule.Rule) (*combinedBatch, error) {
+	batch := &combinedBatch{keys: keys}
+	alternatives := make([]string, 0, len(keys))
+	group := 1
+	for _, key := range keys {
+		rule := rules[key]
+		regex, err := regexp.Compile(rule.ScanPattern())
+		if err != nil {
+			return nil, err
+		}
+		anchored, err := regexp.Compile(`(?m)(?i)\A(?:` + rule.FRegex + `)`)
+		if err != nil {
+			return nil, err
+		}
+		leading, err := regexp.Compile(`(?m)(?i)\A(?s:.)(?:` + rule.FRegex + `)`)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, "("+rule.FRegex+")")
+		batch.groups = append(batch.groups, group)
+		batch.anchored = append(batch.anchored, anchored)
+		batch.leading = append(batch.leading, leading)
+		group += 1 + regex.NumSubexp()
+	}
+
+	regex, err := regexp.Compile("(?m)(?i)(?:" + strings.Join(alternatives, "|") + ")")
+	if err != nil {
+		return nil, err
+	}
+	batch.regex = regex
+	return batch, nil
+}
+
+// candidates 返回批次中可能在内容中有匹配的规则key
+// 合并正则在每个位置只报告一个分支, 没有报告的规则只可能从已报告的匹配区间内开始匹配, 使用锚定正则逐个位置检查
+func (b *combinedBatch) candidates(content string, found map[string]bool) {
+	matches := b.regex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	reported := make([]bool, len(b.keys))
+	for _, match := range matches {
+		for i, group := range b.groups {
+			if match[2*group] >= 0 {
+				reported[i] = true
+				break
+			}
+		}
+	}
+
+	for i, key := range b.keys {
+		if reported[i] || b.matchesWithin(i, content, matches) {
+			found[key] = true
+		}
+	}
+}
+
+// matchesWithin 判断第 i 个规则是否可以从任一匹配区间内的位置开始匹配
+func (b *combinedBatch) matchesWithin(i int, content string, matches [][]int) bool {
+	for _, match := range matches {
+		start, end := match[0], max(match[1], match[0]+1)
+		for position := start; position < end && position <= len(content); position++ {
+			if position < len(content) && !utf8.RuneStart(content[position]) {
+				continue
+			}
+			if b.matchesAt(i, content, position) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAt 判断第 i 个规则是否可以从指定位置开始匹配
+// 截取内容时带上前一个字符, 使位置处的 \b、\B 与多行 ^ 得到与完整内容相同的判断
+func (b *combinedBatch) matchesAt(i int, content string, position int) bool {
+	if position == 0 {
+		return b.anchored[i].MatchString(content)
+	}
+	_, size := utf8.DecodeLastRuneInString(content[:position])
+	return b.leading[i].MatchString(content[position-size:])
+}
+
+// combinedHits 单次内容的合并正则匹配结果
+type combinedHits struct {
+	matcher *combinedMatcher
+	found   map[string]bool
+}
+
+// match 对内容执行合并正则, 成员规则都被关键字预过滤跳过的批次不再执行
+func (m *combinedMatcher) match(content string, allowed func(key string) bool) combinedHits {
+	hits := combinedHits{matcher: m, found: make(map[string]bool)}
+	for _, batch := range m.batches {
+		if slices.ContainsFunc(batch.keys, allowed) {
+			batch.candidates(content, hits.found)
+		}
+	}
+	return hits
+}
+
+// allowed 判断规则是否需要执行正则: 未参与合并或合并正则发现了候选匹配
+func (h combinedHits) allowed(key string) bool {
+	if h.matcher == nil || !h.matcher.members[key] {
+		return true
+	}
+	return h.found[key]
+}
@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestRuleEngineCombinedRegex 测试合并正则不改变扫描结果, 包括被其他分支遮挡的重叠匹配
+func TestRuleEngineCombinedRegex(t *testing.T) {
+	rules := baserule.RuleMap{
+		"Test Group": []baserule.Rule{
+			{Name: "Key", FRegex: `key[_-]?[a-z0-9]{8,}`, Engine: "go", Loaded: true},
+			{Name: "Hex", FRegex: `[0-9a-f]{12,}`, Engine: "go", Loaded: true},
+			{Name: "Same Start", FRegex: `key[_-][0-9]{10}`, Engine: "go", Loaded: true},
+			{Name: "Boundary", FRegex: `\b[a-z]{3}_[0-9]{6}\b`, Engine: "go", Loaded: true},
+			{Name: "Line", FRegex: `^passwd\s*=\s*(\S{6,})$`, SecretGroup: 1, Engine: "go", Loaded: true},
+			{Name: "Non Boundary", FRegex: `\Bsecret[0-9]{4}`, Engine: "go", Loaded: true},
+			{Name: "Word Dot", FRegex: `[a-z]{3}\.[a-z]{6}[0-9]{4}`, Engine: "go", Loaded: true},
+			{Name: "Dot Boundary", FRegex: `\b\.secret[0-9]{4}`, Engine: "go", Loaded: true},
+			{Name: "Phone", FRegex: `1[3-9]\d{9}`, Engine: "java", Loaded: true},
+		},
+	}
+	contents := []string{
+		// Hex 被 Key 遮挡, Same Start 与 Key 起点相同
+		"token key_0123456789abcdef end",
+		"key-0123456789 and abc_123456 mysecret1234",
+		"first line\npasswd = hunter22\nphone 13812345678",
+		// Dot Boundary 被 Word Dot 遮挡, 匹配起点的 \b 依赖左侧的字母
+		"abc.secret1234",
+		"nothing interesting here",
+		"",
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	engine.EnableCombinedRegex()
+	if engine.combined == nil || len(engine.combined.members) != 8 {
+		t.Fatalf("expected 8 combined rules, got %+v", engine.combined)
+	}
+	baseline, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+
+	for _, content := range contents {
+		assertSameResults(t, content, engine, baseline)
+	}
+
+	// 随机内容
+	random := rand.New(rand.NewSource(1))
+	alphabet := "key_-0123456789abcdef \n=passwd."
+	for n := 0; n < 200; n++ {
+		var builder strings.Builder
+		for i := random.Intn(120); i > 0; i-- {
+			builder.WriteByte(alphabet[random.Intn(len(alphabet))])
+		}
+		assertSameResults(t, builder.String(), engine, baseline)
+	}
+}
+
+// TestRuleEngineCombinedRegexEmbedded 测试默认规则在合并正则下的扫描结果与逐条执行一致
+func TestRuleEngineCombinedRegexEmbedded(t *testing.T) {
+	rules := loadEmbeddedRules(t)
+	content := loadSensitiveSample(t)
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	engine.EnableCombinedRegex()
+	if engine.combined == nil {
+		t.Fatal("expected combined regex for embedded rules")
+	}
+	baseline, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+
+	if results := baseline.ApplyRules(content, "a.txt", 0, 1); len(results) == 0 {
+		t.Fatal("expected results on sensitive sample")
+	}
+	assertSameResults(t, content, engine, baseline)
+}
+
+// loadEmbeddedRules 加载内置规则中默认启用的规则
+func loadEmbeddedRules(tb testing.TB) baserule.RuleMap {
+	tb.Helper()
+	config, err := baserule.LoadRulesYaml("../embeds/config.yaml")
+	if err != nil {
+		tb.Fatalf("LoadRulesYaml failed: %v", err)
+	}
+	return config.FilterRules(baserule.FilterOptions{})
+}
+
+// loadSensitiveSample 读取包含各类敏感信息的测试样例
+func loadSensitiveSample(tb testing.TB) string {
+	tb.Helper()
+	sample, err := os.ReadFile("../../testdata/test_sensitive.txt")
+	if err != nil {
+		tb.Fatalf("ReadFile failed: %v", err)
+	}
+	return string(sample)
+}
+
+// assertSameResults 比较两个规则引擎对同一内容的扫描结果
+func assertSameResults(t *testing.T, content string, engine, baseline *RuleEngine) {
+	t.Helper()
+	actual := resultKeys(engine.ApplyRules(content, "a.txt", 0, 1))
+	expected := resultKeys(baseline.ApplyRules(content, "a.txt", 0, 1))
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("content %q: combined results %v, expected %v", content, actual, expected)
+	}
+}
+
+// BenchmarkApplyRules 对比默认规则中 Go 引擎规则在逐条执行、关键字预过滤与合并正则下的扫描耗时
+func BenchmarkApplyRules(b *testing.B) {
+	rules := make(baserule.RuleMap)
+	for groupName, ruleList := range loadEmbeddedRules(b) {
+		for _, rule := range ruleList {
+			if matcher, err := rule.CompileMatcher(); err == nil && baserule.MatcherEngine(matcher) == baserule.RegexEngineGo {
+				rules[groupName] = append(rules[groupName], rule)
+			}
+		}
+	}
+	sample := loadSensitiveSample(b)
+
+	// 少量敏感信息混在大量普通代码中
+	random := rand.New(rand.NewSource(1))
+	words := []string{"func", "return", "value", "config", "user", "index", "for", "if", "err", "nil", "string", "=", ":=", "(", ")", "{", "}"}
+	var builder strings.Builder
+	for builder.Len() < 256*1024 {
+		for i := 0; i < 12; i++ {
+			builder.WriteString(words[random.Intn(len(words))])
+			builder.WriteByte(' ')
+		}
+		builder.WriteByte('\n')
+	}
+	builder.WriteString(sample)
+	content := builder.String()
+
+	cases := []struct {
+		name      string
+		prefilter bool
+		combined  bool
+	}{
+		{name: "sequential", prefilter: false, combined: false},
+		{name: "combined", prefilter: false, combined: true},
+		{name: "prefilter", prefilter: true, combined: false},
+		{name: "prefilter+combined", prefilter: true, combined: true},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			engine, err := NewRuleEngine(rules)
+			if err != nil {
+				b.Fatalf("NewRuleEngine failed: %v", err)
+			}
+			if !tc.prefilter {
+				engine.DisablePrefilter()
+			}
+			if tc.combined {
+				engine.EnableCombinedRegex()
+			}
+			b.SetBytes(int64(len(content)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				engine.ApplyRules(content, "bench.txt", 0, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 编码类型, 用于结果的解码链
+const (
+	EncodingBase64 = "base64"
+	EncodingURL    = "url"
+	EncodingHex    = "hex"
+)
+
+// 解码默认限制
+const (
+	DefaultDecodeDepth   = 2         // 默认最大解码层数
+	DefaultDecodeMaxSize = 64 * 1024 // 默认单个编码内容的最大长度(字节)
+
+	minBase64Length  = 16   // base64 编码内容的最小长度
+	minHexLength     = 16   // hex 编码内容的最小长度
+	minDecodedLength = 6    // 解码后内容的最小长度(引擎会丢弃不超过5个字符的匹配)
+	minPrintable     = 0.95 // 解码后内容中可打印字符的最小比例
+	maxEncodedOutput = 256  // 结果中输出的编码内容的最大长度
+)
+
+// DecodeOptions 解码后重新扫描的配置
+type DecodeOptions struct {
+	Depth   int `json:"depth"`    // 最大解码层数(如 base64 内的 url 编码为2层)
+	MaxSize int `json:"max_size"` // 单个编码内容的最大长度(字节), 超过时不解码
+}
+
+// encodedBlob 内容中检测到的编码内容
+type encodedBlob struct {
+	start    int    // 编码内容在内容中的字节偏移
+	text     string // 编码内容
+	encoding string // 编码类型
+	decoded  string // 解码后的内容
+}
+
+var (
+	base64Pattern = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}={0,2}`)
+	hexPattern    = regexp.MustCompile(`[0-9A-Fa-f]{16,}`)
+	urlPattern    = regexp.MustCompile(`[A-Za-z0-9._~+/=:&?-]*(?:%[0-9A-Fa-f]{2}[A-Za-z0-9._~+/=:&?-]*)+`)
+)
+
+// decoder 检测内容中的 base64/url/hex 编码内容, 解码后重新应用规则
+type decoder struct {
+	depth   int
+	maxSize int
+}
+
+// newDecoder 创建解码器, 未配置的限制使用默认值
+func newDecoder(options *DecodeOptions) *decoder {
+	if options == nil {
+		return nil
+	}
+	d := &decoder{depth: options.Depth, maxSize: options.MaxSize}
+	if d.depth <= 0 {
+		d.depth = DefaultDecodeDepth
+	}
+	if d.maxSize <= 0 {
+		d.maxSize = DefaultDecodeMaxSize
+	}
+	return d
+}
+
+// decodedResult 解码后内容的扫描结果及匹配值在编码内容中对应的区间
+type decodedResult struct {
+	result ScanResult
+	start  int // 匹配值在编码内容中对应区间的起始字节偏移
+	end    int // 匹配值在编码内容中对应区间的结束字节偏移
+}
+
+// rescan 解码内容中的编码内容并重新应用规则, found 为直接扫描该内容的结果
+// 结果的位置与编码内容为匹配值在最外层编码内容中对应的区间, 上下文为解码后的内容, 解码链记录各层编码类型
+func (d *decoder) rescan(engine *RuleEngine, content string, found []ScanResult, filePath string, positionOffset int, startLineNumber int) []ScanResult {
+	blobs := d.findBlobs(content)
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	// 直接扫描结果的位置转换为相对于内容的位置
+	direct := make([]ScanResult, len(found))
+	for i, result := range found {
+		direct[i] = result
+		direct[i].Position -= positionOffset
+	}
+
+	var results []ScanResult
+	lineNumber, counted := startLineNumber, 0
+	for _, blob := range blobs {
+		// 编码内容不包含换行, 其中的结果与编码内容位于同一行
+		lineNumber += strings.Count(content[counted:blob.start], "\n")
+		counted = blob.start
+
+		for _, decoded := range d.scanDecoded(engine, blob, direct, filePath, blob.encoding, 1) {
+			result := decoded.result
+			result.Position = positionOffset + blob.start + decoded.start
+			result.LineNumber = lineNumber
+			result.Encoded = truncateEncoded(blob.text[decoded.start:decoded.end])
+			result.EncodedSize = decoded.end - decoded.start
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// scanDecoded 对解码后的内容应用规则, 未达到最大层数时继续解码其中的编码内容
+// found 为扫描包含该编码内容的内容得到的结果(位置相对于该内容), 同一规则在编码内容范围内已匹配到的相同值会被丢弃
+func (d *decoder) scanDecoded(engine *RuleEngine, blob encodedBlob, found []ScanResult, filePath, chain string, depth int) []decodedResult {
+	undecoded := make(map[string]bool)
+	for _, result := range found {
+		if result.Analysis == "" && result.Position >= blob.start && result.Position < blob.start+len(blob.text) {
+			undecoded[result.Group+"\x00"+result.RuleName+"\x00"+result.Match] = true
+		}
+	}
+
+	direct := engine.ApplyRules(blob.decoded, filePath, 0, 1)
+	var results []decodedResult
+	var parent decodedResult
+	dropped := false
+	for _, result := range direct {
+		// 分析结果跟随其匹配结果, 使用相同的区间
+		if result.Analysis != "" {
+			if !dropped {
+				result.DecodeChain = chain
+				results = append(results, decodedResult{result: result, start: parent.start, end: parent.end})
+			}
+			continue
+		}
+		dropped = undecoded[result.Group+"\x00"+result.RuleName+"\x00"+result.Match]
+		if dropped {
+			continue
+		}
+		result.DecodeChain = chain
+		start, end := blob.encodedSpan(result.Position, result.Position+len(result.Match))
+		parent = decodedResult{result: result, start: start, end: end}
+		results = append(results, parent)
+	}
+
+	if depth < d.depth {
+		for _, inner := range d.findBlobs(blob.decoded) {
+			for _, decoded := range d.scanDecoded(engine, inner, direct, filePath, chain+">"+inner.encoding, depth+1) {
+				decoded.start, decoded.end = blob.encodedSpan(inner.start+decoded.start, inner.start+decoded.end)
+				results = append(results, decoded)
+			}
+		}
+	}
+	return results
+}
+
+// encodedSpan 返回解码后内容中的字节区间在编码内容中对应的区间
+func (b encodedBlob) encodedSpan(start, end int) (int, int) {
+	switch b.encoding {
+	case EncodingBase64:
+		// 每3个字节编码为4个字符, 区间扩展到完整的编码组
+		return start / 3 * 4, min(len(b.text), (end+2)/3*4)
+	case EncodingHex:
+		return start * 2, end * 2
+	case EncodingURL:
+		return urlOffset(b.text, start), urlOffset(b.text, end)
+	}
+	return 0, len(b.text)
+}
+
+// urlOffset 返回 url 解码后内容中的字节偏移在编码内容中的位置, 百分号转义占3个字符
+func urlOffset(text string, decodedOffset int) int {
+	offset := 0
+	for i := 0; i < decodedOffset && offset < len(text); i++ {
+		if text[offset] == '%' {
+			offset += 3
+		} else {
+			offset++
+		}
+	}
+	return offset
+}
+
+// findBlobs 按位置顺序返回内容中可以解码为文本的编码内容
+func (d *decoder) findBlobs(content string) []encodedBlob {
+	var blobs []encodedBlob
+	for _, candidate := range []struct {
+		encoding string
+		pattern  *regexp.Regexp
+		decode   func(string) (string, bool)
+	}{
+		{EncodingBase64, base64Pattern, decodeBase64},
+		{EncodingURL, urlPattern, decodeURL},
+		{EncodingHex, hexPattern, decodeHex},
+	} {
+		for _, loc := range candidate.pattern.FindAllStringIndex(content, -1) {
+			text := content[loc[0]:loc[1]]
+			if len(text) > d.maxSize {
+				continue
+			}
+			if decoded, ok := candidate.decode(text); ok {
+				blobs = append(blobs, encodedBlob{start: loc[0], text: text, encoding: candidate.encoding, decoded: decoded})
+			}
+		}
+	}
+
+	sort.SliceStable(blobs, func(i, j int) bool {
+		return blobs[i].start < blobs[j].start
+	})
+	return blobs
+}
+
+// decodeBase64 按标准或 URL 安全字母表解码 base64, 支持省略填充
+func decodeBase64(text string) (string, bool) {
+	if len(strings.TrimRight(text, "=")) < minBase64Length {
+		return "", false
+	}
+
+	urlSafe := strings.ContainsAny(text, "-_")
+	if urlSafe && strings.ContainsAny(text, "+/") {
+		return "", false
+	}
+	encoding := base64.StdEncoding
+	if urlSafe {
+		encoding = base64.URLEncoding
+	}
+	if !strings.HasSuffix(text, "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+
+	decoded, err := encoding.DecodeString(text)
+	if err != nil {
+		return "", false
+	}
+	return decodedText(decoded)
+}
+
+// decodeURL 解码包含百分号转义的 url 编码内容
+func decodeURL(text string) (string, bool) {
+	decoded, err := url.QueryUnescape(text)
+	if err != nil || decoded == text {
+		return "", false
+	}
+	return decodedText([]byte(decoded))
+}
+
+// decodeHex 解码偶数长度的 hex 编码内容
+func decodeHex(text string) (string, bool) {
+	if len(text) < minHexLength || len(text)%2 != 0 {
+		return "", false
+	}
+	decoded, err := hex.DecodeString(text)
+	if err != nil {
+		return "", false
+	}
+	return decodedText(decoded)
+}
+
+// decodedText 检查解码结果是否为文本: 有效的 UTF-8 且几乎全部为可打印字符, 二进制内容(如图片、哈希值)被忽略
+func decodedText(decoded []byte) (string, bool) {
+	if len(decoded) < minDecodedLength || !utf8.Valid(decoded) {
+		return "", false
+	}
+
+	text := string(decoded)
+	total, printable := 0, 0
+	for _, r := range text {
+		total++
+		if unicode.IsPrint(r) || r == '\n' || r == '\r' || r == '\t' {
+			printable++
+		}
+	}
+	if float64(printable) < float64(total)*minPrintable {
+		return "", false
+	}
+	return text, true
+}
+
+// truncateEncoded 截断结果中输出的编码内容
+func truncateEncoded(text string) string {
+	if len(text) <= maxEncodedOutput {
+		return text
+	}
+	return text[:maxEncodedOutput] + "..."
+}
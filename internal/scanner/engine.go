@@ -3,22 +3,33 @@ package scanner
 import (
 	"fmt"
 	"github.com/winezer0/xutils/logging"
+	"sort"
 	"strings"
+	"time"
 
 	"privacycheck/internal/baserule"
 )
 
 // RuleEngine 规则引擎
 type RuleEngine struct {
-	rules       baserule.RuleMap
-	compiledReg map[string]baserule.RegexMatcher
+	rules         baserule.RuleMap
+	compiledReg   map[string]baserule.RegexMatcher
+	compiledAllow map[string]*baserule.CompiledAllowlist
+	keyRules      map[string]baserule.Rule  // 规则key -> 规则
+	prefilter     *rulePrefilter            // 关键字预过滤器, 为nil时所有规则都执行正则
+	combined      *combinedMatcher          // 合并正则预过滤器, 为nil时不合并
+	profiler      *RuleProfiler             // 规则性能统计, 为nil时不统计
+	analyzer      *baserule.AnalyzerContext // 规则分析器配置
 }
 
 // NewRuleEngine 创建新的规则引擎
 func NewRuleEngine(rules baserule.RuleMap) (*RuleEngine, error) {
 	engine := &RuleEngine{
-		rules:       rules,
-		compiledReg: make(map[string]baserule.RegexMatcher),
+		rules:         rules,
+		compiledReg:   make(map[string]baserule.RegexMatcher),
+		compiledAllow: make(map[string]*baserule.CompiledAllowlist),
+		keyRules:      make(map[string]baserule.Rule),
+		analyzer:      &baserule.AnalyzerContext{},
 	}
 
 	// 预编译所有正则表达式
@@ -35,54 +46,120 @@ func (e *RuleEngine) compileRegexes() error {
 		for i, rule := range ruleList {
 			key := fmt.Sprintf("%s_%d", groupName, i)
 
-			// 编译正则表达式
-			pattern := rule.FRegex
-			//IgnoreCase
-			pattern = "(?i)" + pattern
-			// 添加多行模式
-			pattern = "(?m)" + pattern
-
-			var matcher baserule.RegexMatcher
-			var err error
-
-			// 根据规则指定的引擎
-			if rule.Engine == "dfa" || rule.Engine == "nfa" {
-				rule.Engine = string(baserule.RegexEngineJava)
+			// 编译正则表达式(忽略大小写 + 多行模式)
+			matcher, err := rule.CompileMatcher()
+			if err != nil {
+				return fmt.Errorf("failed to compile regex [%s:%s]: %w", groupName, rule.Name, err)
 			}
 
-			if rule.Engine != "" {
-				// 使用指定的引擎
-				engine := baserule.RegexEngine(rule.Engine)
-				matcher, err = baserule.NewRegexMatcher(pattern, engine)
-				if err != nil {
-					return fmt.Errorf("failed to compile regex with specified engine [%s:%s]: %w", groupName, rule.Name, err)
-				}
-			} else {
-				// 引擎为空，先尝试 Go 引擎，失败则使用 Java 引擎
-				matcher, err = baserule.TryCompileWithFallback(pattern)
+			e.compiledReg[key] = matcher
+			e.keyRules[key] = rule
+
+			// 编译白名单
+			if !rule.Allowlist.IsEmpty() {
+				allowlist, err := rule.Allowlist.Compile()
 				if err != nil {
-					return fmt.Errorf("failed to compile regex with fallback [%s:%s]: %w", groupName, rule.Name, err)
+					return fmt.Errorf("failed to compile allowlist [%s:%s]: %w", groupName, rule.Name, err)
 				}
+				e.compiledAllow[key] = allowlist
 			}
-
-			e.compiledReg[key] = matcher
 		}
 	}
 
 	logging.Infof("successfully compiled %d regex patterns", len(e.compiledReg))
+
+	// 构建关键字预过滤器
+	e.prefilter = newRulePrefilter(e.keyRules, e.compiledReg)
+	if e.prefilter != nil {
+		logging.Infof("keyword prefilter enabled for %d/%d rules (%d keywords)", len(e.prefilter.ruleKeywords), len(e.keyRules), e.prefilter.matcher.count)
+	}
 	return nil
 }
 
+// DisablePrefilter 关闭关键字预过滤, 所有规则都对全部内容执行正则
+func (e *RuleEngine) DisablePrefilter() {
+	e.prefilter = nil
+}
+
+// EnableCombinedRegex 将 Go 引擎规则按批次合并为一个正则, 一次遍历找出候选规则后再由各规则精确提取
+func (e *RuleEngine) EnableCombinedRegex() {
+	var keys []string
+	rules := make(map[string]baserule.Rule)
+	for groupName, ruleList := range e.rules {
+		for i, rule := range ruleList {
+			key := fmt.Sprintf("%s_%d", groupName, i)
+			keys = append(keys, key)
+			rules[key] = rule
+		}
+	}
+	sort.Strings(keys)
+
+	e.combined = newCombinedMatcher(keys, rules, e.compiledReg)
+	if e.combined != nil {
+		logging.Infof("combined regex enabled for %d/%d rules (%d batches)", len(e.combined.members), len(keys), len(e.combined.batches))
+	}
+}
+
+// EnableProfiling 启用规则性能统计, 需在扫描开始前调用
+func (e *RuleEngine) EnableProfiling() *RuleProfiler {
+	e.profiler = newRuleProfiler()
+	for groupName, ruleList := range e.rules {
+		for i, rule := range ruleList {
+			key := fmt.Sprintf("%s_%d", groupName, i)
+			e.profiler.register(key, RuleProfile{
+				Group:    groupName,
+				RuleID:   rule.ID,
+				RuleName: rule.Name,
+				Engine:   string(baserule.MatcherEngine(e.compiledReg[key])),
+			})
+		}
+	}
+	return e.profiler
+}
+
+// SetAnalyzerWordlist 设置 JWT 等分析器额外使用的弱密钥字典
+func (e *RuleEngine) SetAnalyzerWordlist(wordlist []string) {
+	e.analyzer.Wordlist = wordlist
+}
+
+// Profiler 返回规则性能统计器, 未启用时返回nil
+func (e *RuleEngine) Profiler() *RuleProfiler {
+	return e.profiler
+}
+
 // ApplyRules 对内容应用所有规则，支持指定偏移量和起始行号
 func (e *RuleEngine) ApplyRules(content, filePath string, positionOffset int, startLineNumber int) []ScanResult {
 	var results []ScanResult
 
+	// 一次遍历找出内容中出现的关键字
+	var hits prefilterHits
+	if e.prefilter != nil {
+		hits = e.prefilter.match(content)
+	}
+	// 一次遍历合并正则找出可能匹配的规则
+	var candidates combinedHits
+	if e.combined != nil {
+		candidates = e.combined.match(content, hits.allowed)
+	}
+
 	for groupName, ruleList := range e.rules {
 		for i, rule := range ruleList {
 			key := fmt.Sprintf("%s_%d", groupName, i)
 			regex := e.compiledReg[key]
 
-			ruleResults := e.applyRule(rule, regex, content, groupName, filePath, positionOffset, startLineNumber)
+			// 内容中没有规则的关键字或合并正则没有发现候选匹配时跳过正则匹配
+			if !hits.allowed(key) || !candidates.allowed(key) {
+				if e.profiler != nil {
+					e.profiler.skip(key)
+				}
+				continue
+			}
+
+			start := time.Now()
+			ruleResults, matches, err := e.applyRule(rule, regex, e.compiledAllow[key], content, groupName, key, filePath, positionOffset, startLineNumber)
+			if e.profiler != nil {
+				e.profiler.record(key, time.Since(start), len(content), matches, len(ruleResults), err)
+			}
 			results = append(results, ruleResults...)
 		}
 	}
@@ -90,44 +167,68 @@ func (e *RuleEngine) ApplyRules(content, filePath string, positionOffset int, st
 	return results
 }
 
+// ApplyRule 使用已编译的正则对内容应用单条规则, 提取、熵检测、校验器与白名单检查与扫描时一致
+// 不创建规则引擎(不输出编译与预过滤日志), 用于规则测试逐条执行样例; 匹配过程中的错误与扫描时一样只输出警告
+func ApplyRule(groupName string, rule baserule.Rule, matcher baserule.RegexMatcher, content, filePath string) ([]ScanResult, error) {
+	var allowlist *baserule.CompiledAllowlist
+	if !rule.Allowlist.IsEmpty() {
+		compiled, err := rule.Allowlist.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile allowlist [%s:%s]: %w", groupName, rule.Name, err)
+		}
+		allowlist = compiled
+	}
+
+	engine := &RuleEngine{analyzer: &baserule.AnalyzerContext{}}
+	results, _, _ := engine.applyRule(rule, matcher, allowlist, content, groupName, "", filePath, 0, 1)
+	return results, nil
+}
+
 // applyRule 应用单个规则，支持指定偏移量和起始行号
-func (e *RuleEngine) applyRule(rule baserule.Rule, matcher baserule.RegexMatcher, content, groupName, filePath string, positionOffset int, startLineNumber int) []ScanResult {
+// 返回输出的结果、正则命中次数以及匹配过程中的错误(如 regexp2 超时)
+func (e *RuleEngine) applyRule(rule baserule.Rule, matcher baserule.RegexMatcher, allowlist *baserule.CompiledAllowlist, content, groupName, key, filePath string, positionOffset int, startLineNumber int) ([]ScanResult, int, error) {
 	var results []ScanResult
+	matches := 0
 
 	// 查找第一个匹配
 	match, err := matcher.FindStringMatch(content)
 	if err != nil {
 		logging.Warnf("error matching regex [%s:%s]: %v", groupName, rule.Name, err)
-		return results
+		return results, matches, err
 	}
 
 	// 遍历所有匹配
 	for match != nil {
-		matchedText := match.String()
+		matches++
+		matchedText, start := rule.ExtractSpan(match)
 
 		// 过滤过短的匹配
 		if len(strings.TrimSpace(matchedText)) <= 5 {
-			nextMatch, err := match.FindNextMatch()
-			if err != nil {
+			if match, err = match.FindNextMatch(); err != nil {
 				break
 			}
-			match = nextMatch
 			continue
 		}
 
-		// 查找匹配在原文中的位置
-		start := strings.Index(content, matchedText)
-		if start == -1 {
-			nextMatch, err := match.FindNextMatch()
-			if err != nil {
+		// 提取的分组未参与匹配
+		if start < 0 {
+			if match, err = match.FindNextMatch(); err != nil {
 				break
 			}
-			match = nextMatch
 			continue
 		}
 
 		end := start + len(matchedText)
 
+		// 熵检测、校验器和白名单检查, 丢弃随机性不足、校验失败或命中白名单的结果
+		entropy, ok := rule.CheckEntropy(matchedText)
+		if !ok || !rule.CheckValidator(matchedText) || allowlist.Allowed(matchedText, filePath) {
+			if match, err = match.FindNextMatch(); err != nil {
+				break
+			}
+			continue
+		}
+
 		// 计算上下文
 		contextLeft := rule.ContextLeft
 		contextRight := rule.ContextRight
@@ -145,25 +246,76 @@ func (e *RuleEngine) applyRule(rule baserule.Rule, matcher baserule.RegexMatcher
 		result := ScanResult{
 			File:       filePath,
 			Group:      groupName,
+			RuleID:     rule.ID,
 			RuleName:   rule.Name,
 			Match:      matchedText,
 			Context:    context,
 			Position:   positionOffset + start,                                 // 加上位置偏移
 			LineNumber: startLineNumber + strings.Count(content[:start], "\n"), // 计算行号（考虑起始行号偏移）
 			Sensitive:  rule.Sensitive,
+			Severity:   rule.GetSeverity(),
+			Confidence: rule.GetConfidence(),
+			Category:   rule.Category,
+			Tags:       rule.Tags,
+			Entropy:    entropy,
+
+			Description: rule.Description,
+			Remediation: rule.Remediation,
+			References:  rule.References,
+
+			RuleKey:  key,
+			Priority: rule.Priority,
 		}
 
 		results = append(results, result)
 
+		// 规则配置了分析器时, 分析结果作为独立的结果输出
+		for _, finding := range rule.Analyze(matchedText, e.analyzer) {
+			results = append(results, analyzedResult(result, finding))
+		}
+
 		// 查找下一个匹配
-		nextMatch, err := match.FindNextMatch()
-		if err != nil {
+		if match, err = match.FindNextMatch(); err != nil {
 			break
 		}
-		match = nextMatch
 	}
 
-	return results
+	return results, matches, err
+}
+
+// AnalyzeResults 对缓存中读取的正则匹配结果重新执行规则分析器, 分析结果紧随被分析的匹配
+// 分析结果依赖当前时间(如令牌与证书的过期时间), 不写入缓存; 按结果的规则key找到产生匹配的规则
+func (e *RuleEngine) AnalyzeResults(results []ScanResult) []ScanResult {
+	analyzed := make([]ScanResult, 0, len(results))
+	for _, result := range results {
+		analyzed = append(analyzed, result)
+		if result.Analysis != "" {
+			continue
+		}
+		rule, ok := e.keyRules[result.RuleKey]
+		if !ok || rule.Analyzer == "" {
+			continue
+		}
+		for _, finding := range rule.Analyze(result.Match, e.analyzer) {
+			analyzed = append(analyzed, analyzedResult(result, finding))
+		}
+	}
+	return analyzed
+}
+
+// analyzedResult 根据分析器的分析结果创建结果, 位置与上下文与被分析的匹配相同
+func analyzedResult(result ScanResult, finding baserule.Finding) ScanResult {
+	result.Analysis = finding.Type
+	result.Match = finding.Value
+	result.Description = finding.Description
+	result.Entropy = 0
+	if finding.Severity != "" {
+		result.Severity = finding.Severity
+	}
+	if finding.Category != "" {
+		result.Category = finding.Category
+	}
+	return result
 }
 
 // 辅助函数
@@ -190,3 +190,115 @@ func TestRuleEngineWithMultipleRules(t *testing.T) {
 		t.Errorf("Expected to find '123-456-7890', but didn't")
 	}
 }
+
+// TestRuleEngineWithEntropy 测试熵检测规则与捕获组提取
+func TestRuleEngineWithEntropy(t *testing.T) {
+	rules := baserule.RuleMap{
+		"test": {
+			{
+				Name:           "High Entropy Secret",
+				FRegex:         `secret\s*=\s*"([a-z0-9]+)"`,
+				Engine:         "go",
+				Loaded:         true,
+				SecretGroup:    1,
+				Entropy:        4.3,
+				EntropyCharset: baserule.EntropyCharsetBase64,
+			},
+		},
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+
+	content := "secret = \"aaaaaaaaaaaaaaaaaaaa\"\nsecret = \"Zx8Q2mP7vL4kR9tW1yB6nC3dF5gH\""
+	results := engine.ApplyRules(content, "test.txt", 0, 1)
+
+	if len(results) != 1 {
+		t.Fatalf("Expected exactly one match, but got %d", len(results))
+	}
+	if results[0].Match != "Zx8Q2mP7vL4kR9tW1yB6nC3dF5gH" {
+		t.Errorf("Expected capture group value, got %q", results[0].Match)
+	}
+	if results[0].Entropy <= 4.3 {
+		t.Errorf("Expected entropy above threshold, got %f", results[0].Entropy)
+	}
+	if results[0].LineNumber != 2 {
+		t.Errorf("Expected line number 2, got %d", results[0].LineNumber)
+	}
+}
+
+func TestRuleEngineProfiling(t *testing.T) {
+	rules := baserule.RuleMap{
+		"Test Group": []baserule.Rule{
+			{Name: "Digits", FRegex: `\d{6,}`, Engine: "go", Loaded: true},
+			{Name: "Never", FRegex: `zzzzzz`, Keywords: []string{"zzzzzz"}, Engine: "java", Loaded: true},
+		},
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	if engine.Profiler() != nil {
+		t.Fatalf("Expected profiling to be disabled by default")
+	}
+	profiler := engine.EnableProfiling()
+
+	content := "id 123456 and 7654321"
+	engine.ApplyRules(content, "a.txt", 0, 1)
+	engine.ApplyRules(content, "b.txt", 0, 1)
+
+	profiles := profiler.Profiles()
+	if len(profiles) != 2 {
+		t.Fatalf("Expected 2 rule profiles, got %d", len(profiles))
+	}
+	byName := make(map[string]RuleProfile)
+	for _, profile := range profiles {
+		byName[profile.RuleName] = profile
+	}
+
+	digits := byName["Digits"]
+	if digits.Engine != "go" || digits.Calls != 2 || digits.Bytes != int64(2*len(content)) || digits.Matches != 4 || digits.Results != 4 {
+		t.Errorf("Unexpected profile for Digits: %+v", digits)
+	}
+	// 内容中不包含配置的关键字 zzzzzz, 规则被预过滤跳过
+	never := byName["Never"]
+	if never.Engine != "java" || never.Calls != 0 || never.Skipped != 2 || never.Matches != 0 || never.Timeouts != 0 {
+		t.Errorf("Unexpected profile for Never: %+v", never)
+	}
+}
+
+// TestRuleEngineWithAnalyzer 测试规则分析器的结果作为独立结果输出
+func TestRuleEngineWithAnalyzer(t *testing.T) {
+	rules := baserule.RuleMap{
+		"test": {
+			{
+				Name:     "JSON Web Token",
+				FRegex:   `eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`,
+				Engine:   "go",
+				Loaded:   true,
+				Severity: "high",
+				Analyzer: baserule.AnalyzerJWT,
+			},
+		},
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+
+	content := "first line\ntoken: eyJhbGciOiJub25lIn0.eyJzdWIiOiJhZG1pbiIsInJvbGUiOiJhZG1pbiJ9."
+	results := engine.ApplyRules(content, "test.txt", 0, 1)
+	if len(results) != 3 {
+		t.Fatalf("Expected token and 2 analysis results, got %d: %+v", len(results), results)
+	}
+	if results[0].Analysis != "" || results[1].Analysis != "jwt.algorithm" || results[2].Analysis != "jwt.expiry" {
+		t.Errorf("Unexpected analysis types: %q %q %q", results[0].Analysis, results[1].Analysis, results[2].Analysis)
+	}
+	if results[1].Match != "none" || results[1].Severity != baserule.SeverityCritical || results[1].Position != results[0].Position || results[1].LineNumber != 2 {
+		t.Errorf("Unexpected algorithm result: %+v", results[1])
+	}
+}
@@ -0,0 +1,189 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+)
+
+// journalVersion 扫描日志格式版本
+const journalVersion = 3
+
+// journalHeader 扫描日志首行, 记录创建日志时的规则指纹与项目路径
+type journalHeader struct {
+	Version     int    `json:"version"`
+	Fingerprint string `json:"fingerprint"`
+	Project     string `json:"project"`
+}
+
+// journalRecord 扫描日志中单个已完成文件的记录
+type journalRecord struct {
+	File    string       `json:"file"`
+	Size    int64        `json:"size"`
+	ModTime int64        `json:"mod_time"`       // 读取文件前获取的修改时间(纳秒时间戳)
+	Hash    string       `json:"hash,omitempty"` // 扫描内容 SHA-256, 二进制密钥文件为空
+	Results []ScanResult `json:"results"`
+	Rules   []resultRule `json:"rules"` // 结果对应的规则key与优先级(结果中不输出)
+}
+
+// ScanJournal 扫描进度日志(JSON Lines), 每完成一个文件追加一行记录
+// 扫描被中断后使用 --resume 读取日志, 跳过已完成且未修改的文件并合并之前的结果; 扫描结果成功输出后删除日志
+// 日志包含匹配到的敏感信息, 仅文件所有者可读写
+type ScanJournal struct {
+	path      string
+	mu        sync.Mutex
+	file      *os.File
+	completed map[string]journalRecord // 之前的扫描中已完成的文件
+}
+
+// OpenJournal 打开扫描日志
+// resume 为true且日志的规则指纹与项目路径一致时保留已完成的记录, 否则创建新日志
+func OpenJournal(path, fingerprint, project string, resume bool) (*ScanJournal, error) {
+	journal := &ScanJournal{path: path, completed: make(map[string]journalRecord)}
+	header := journalHeader{Version: journalVersion, Fingerprint: fingerprint, Project: project}
+
+	if resume {
+		records, err := readJournal(path, header)
+		if err != nil {
+			logging.Warnf("cannot resume from scan journal %s, starting a new scan: %v", path, err)
+		}
+		for _, record := range records {
+			if info, err := os.Stat(record.File); err == nil && info.Size() == record.Size && info.ModTime().UnixNano() == record.ModTime {
+				journal.completed[record.File] = record
+			}
+		}
+	} else if _, err := os.Stat(path); err == nil {
+		logging.Warnf("scan journal %s of an interrupted scan will be overwritten, use --resume to continue that scan", path)
+	}
+
+	// 重写日志(丢弃被中断时写入一半的记录与已修改文件的记录), 先写入临时文件再替换
+	if err := utils.EnsureDir(path, true); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	temp := path + ".tmp"
+	file, err := os.OpenFile(temp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scan journal %s: %w", path, err)
+	}
+	journal.file = file
+	// 已存在的临时文件保留原有权限, 需要重新设置
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to set scan journal permissions %s: %w", path, err)
+	}
+	if err := journal.writeLine(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	for _, record := range journal.completed {
+		if err := journal.writeLine(record); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if err := os.Rename(temp, path); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to replace scan journal %s: %w", path, err)
+	}
+
+	return journal, nil
+}
+
+// readJournal 读取扫描日志中的已完成记录, 日志不存在或与当前扫描不一致时返回错误
+// 最后一行可能在中断时只写入了一半, 无法解析的记录会被忽略
+func readJournal(path string, expected journalHeader) ([]journalRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("journal does not exist")
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("journal is empty")
+	}
+	var header journalHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("invalid journal header: %w", err)
+	}
+	if header.Version != expected.Version || header.Fingerprint != expected.Fingerprint {
+		return nil, fmt.Errorf("journal was created with different rules")
+	}
+	if header.Project != expected.Project {
+		return nil, fmt.Errorf("journal was created for project %s", header.Project)
+	}
+
+	var records []journalRecord
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// 没有换行结尾的最后一行是被中断的写入
+			break
+		}
+		var record journalRecord
+		if json.Unmarshal(line, &record) == nil && record.File != "" {
+			restoreResultRules(record.Results, record.Rules)
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// writeLine 追加一行JSON, 每行直接写入文件, 进程被终止时已写入的记录不会丢失
+func (j *ScanJournal) writeLine(value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write scan journal %s: %w", j.path, err)
+	}
+	return nil
+}
+
+// Completed 返回之前的扫描中已完成的文件结果及扫描时的文件状态
+func (j *ScanJournal) Completed(filePath string) ([]ScanResult, fileState, bool) {
+	record, ok := j.completed[filePath]
+	return record.Results, fileState{Size: record.Size, ModTime: record.ModTime, Hash: record.Hash}, ok
+}
+
+// CompletedCount 返回之前的扫描中已完成的文件数量
+func (j *ScanJournal) CompletedCount() int {
+	return len(j.completed)
+}
+
+// Record 记录一个已完成的文件, state 为读取文件前获取的文件状态
+// 扫描期间文件被修改时记录的状态与新文件不一致, 恢复扫描时会重新扫描该文件
+func (j *ScanJournal) Record(filePath string, state fileState, results []ScanResult) error {
+	record := journalRecord{File: filePath, Size: state.Size, ModTime: state.ModTime, Hash: state.Hash, Results: results, Rules: resultRules(results)}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.writeLine(record)
+}
+
+// Close 扫描结束后关闭日志, 日志在扫描结果成功输出后由 RemoveJournal 删除
+func (j *ScanJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+// RemoveJournal 删除扫描日志, 日志不存在时不返回错误
+func RemoveJournal(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
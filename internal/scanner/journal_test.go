@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestScanJournal 测试扫描日志的中断恢复: 忽略写入一半的记录、已修改的文件与不同规则的日志
+func TestScanJournal(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "scan.journal")
+	fileA := filepath.Join(dir, "a.txt")
+	fileB := filepath.Join(dir, "b.txt")
+	writeFile(t, fileA, "a")
+	writeFile(t, fileB, "b")
+
+	journal, err := OpenJournal(journalPath, "v1", dir, false)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if err := journal.Record(fileA, scannedState(t, fileA), []ScanResult{{File: fileA, Match: "first", RuleKey: "Keys_1", Priority: 5}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := journal.Record(fileB, scannedState(t, fileB), nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	// 模拟进程被终止: 最后一条记录只写入了一半
+	journal.file.WriteString(`{"file":"c.txt","results":[`)
+	journal.file.Close()
+
+	// 已修改的文件需要重新扫描
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(fileB, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	journal, err = OpenJournal(journalPath, "v1", dir, true)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if journal.CompletedCount() != 1 {
+		t.Fatalf("expected 1 completed file, got %d", journal.CompletedCount())
+	}
+	// 不输出到JSON的规则key与优先级同样被恢复
+	if results, _, ok := journal.Completed(fileA); !ok || len(results) != 1 || results[0].Match != "first" || results[0].RuleKey != "Keys_1" || results[0].Priority != 5 {
+		t.Errorf("expected restored results for %s, got %v %v", fileA, results, ok)
+	}
+	if _, _, ok := journal.Completed(fileB); ok {
+		t.Errorf("expected modified file %s to be scanned again", fileB)
+	}
+	journal.file.Close()
+
+	// 重写后的日志仍然可以恢复
+	journal, err = OpenJournal(journalPath, "v1", dir, true)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if journal.CompletedCount() != 1 {
+		t.Errorf("expected rewritten journal to keep 1 completed file, got %d", journal.CompletedCount())
+	}
+	journal.file.Close()
+
+	// 规则变化或未指定 resume 时重新开始
+	for _, tc := range []struct {
+		fingerprint string
+		resume      bool
+	}{{"v2", true}, {"v1", false}} {
+		journal, err = OpenJournal(journalPath, tc.fingerprint, dir, tc.resume)
+		if err != nil {
+			t.Fatalf("OpenJournal failed: %v", err)
+		}
+		if journal.CompletedCount() != 0 {
+			t.Errorf("fingerprint %s resume %v: expected new journal, got %d completed files", tc.fingerprint, tc.resume, journal.CompletedCount())
+		}
+		journal.file.Close()
+	}
+
+	journal, err = OpenJournal(journalPath, "v1", dir, true)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if info, err := os.Stat(journalPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected journal kept with mode 0600 after close, got %v %v", info, err)
+	}
+	if err := RemoveJournal(journalPath); err != nil {
+		t.Fatalf("RemoveJournal failed: %v", err)
+	}
+	if _, err := os.Stat(journalPath); !os.IsNotExist(err) {
+		t.Errorf("expected journal removed, got %v", err)
+	}
+	if err := RemoveJournal(journalPath); err != nil {
+		t.Errorf("expected no error for missing journal, got %v", err)
+	}
+}
+
+// TestScannerResume 测试恢复扫描时合并之前的结果并只扫描未完成的文件
+func TestScannerResume(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "scan.journal")
+	fileA := filepath.Join(dir, "a.txt")
+	fileB := filepath.Join(dir, "b.txt")
+	writeFile(t, fileA, "api_token = abcdef0123456789abcdef")
+	writeFile(t, fileB, "api_token = 0123456789abcdef0123")
+
+	rules := baserule.RuleMap{
+		"Test Group": []baserule.Rule{{Name: "Token", FRegex: `token\s*=\s*([a-z0-9]{16,})`, SecretGroup: 1, Loaded: true}},
+	}
+	config := &ScanConfig{ProjectPath: dir, Workers: 2, JournalFile: journalPath, Resume: true}
+	instance, err := NewScanner(rules, config)
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+
+	// 之前被中断的扫描已完成 a.txt, 恢复的结果来自日志而不是重新扫描
+	journal, err := OpenJournal(journalPath, instance.fingerprint, dir, false)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if err := journal.Record(fileA, scannedState(t, fileA), []ScanResult{{File: fileA, RuleName: "Token", Match: "from-journal"}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	journal.file.Close()
+
+	results, err := instance.Scan([]string{fileA, fileB})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	matches := make(map[string]string)
+	for _, result := range results {
+		matches[result.File] = result.Match
+	}
+	if len(results) != 2 || matches[fileA] != "from-journal" || matches[fileB] != "0123456789abcdef0123" {
+		t.Errorf("unexpected resumed results: %v", results)
+	}
+	// 扫描结果输出前保留日志, 输出失败时仍可恢复
+	if _, err := os.Stat(journalPath); err != nil {
+		t.Errorf("expected journal kept until the results are written, got %v", err)
+	}
+	instance.FinishJournal()
+	if _, err := os.Stat(journalPath); !os.IsNotExist(err) {
+		t.Errorf("expected journal removed after finish, got %v", err)
+	}
+}
+
+// TestScannerResumeModifiedDuringScan 测试扫描完成后、写入日志前被修改的文件在恢复时重新扫描
+func TestScannerResumeModifiedDuringScan(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "scan.journal")
+	file := filepath.Join(dir, "a.txt")
+	writeFile(t, file, "api_token = abcdef0123456789abcdef")
+
+	rules := baserule.RuleMap{
+		"Test Group": []baserule.Rule{{Name: "Token", FRegex: `token\s*=\s*([a-z0-9]{16,})`, SecretGroup: 1, Loaded: true}},
+	}
+	config := &ScanConfig{ProjectPath: dir, Workers: 1, JournalFile: journalPath, Resume: true}
+	instance, err := NewScanner(rules, config)
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+
+	results, state, err := instance.scanFile(file)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("scanFile failed: %v %v", results, err)
+	}
+	writeFile(t, file, "api_token = 0123456789abcdef0123")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	journal, err := OpenJournal(journalPath, instance.fingerprint, dir, false)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if err := journal.Record(file, state, results); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	journal.file.Close()
+
+	results, err = instance.Scan([]string{file})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Match != "0123456789abcdef0123" {
+		t.Errorf("expected file modified before it was recorded to be scanned again, got %v", results)
+	}
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// 二进制密钥文件结果的规则组与规则名称
+const (
+	KeyFileGroup    = "Key Material"
+	KeyFileRuleName = "Binary Key File"
+)
+
+// keyFileExtensions 可能为 DER 编码证书、私钥或 PKCS#12 的文件扩展名
+var keyFileExtensions = map[string]bool{
+	".der": true, ".cer": true, ".crt": true, ".key": true,
+	".p8": true, ".pk8": true, ".p12": true, ".pfx": true,
+}
+
+// isKeyFileExtension 判断文件扩展名是否可能为二进制密钥文件
+func isKeyFileExtension(filePath string) bool {
+	return keyFileExtensions[strings.ToLower(filepath.Ext(filePath))]
+}
+
+// scanKeyFile 解析二进制的 DER 证书、私钥与 PKCS#12 文件
+// 文件扩展名不匹配、内容不是 DER 编码(如 PEM 文本)或无法识别时返回false, 按文本文件继续扫描
+func (s *Scanner) scanKeyFile(filePath string) ([]ScanResult, bool, error) {
+	if !isKeyFileExtension(filePath) {
+		return nil, false, nil
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	// DER 编码以 SEQUENCE 开头
+	if len(data) == 0 || data[0] != 0x30 {
+		return nil, false, nil
+	}
+
+	ctx := &baserule.AnalyzerContext{Now: time.Now(), Wordlist: s.engine.analyzer.Wordlist}
+	findings := baserule.AnalyzeDER(data, ctx)
+	if len(findings) == 0 {
+		return nil, false, nil
+	}
+
+	// 第一条结果为文件本身, 其余为分析结果
+	file := ScanResult{
+		File:        filePath,
+		Group:       KeyFileGroup,
+		RuleName:    KeyFileRuleName,
+		Match:       filepath.Base(filePath),
+		LineNumber:  1,
+		Sensitive:   findings[0].Category == "credentials",
+		Severity:    findings[0].Severity,
+		Confidence:  baserule.ConfidenceHigh,
+		Category:    findings[0].Category,
+		Description: "DER encoded key material",
+	}
+	results := []ScanResult{file}
+	for _, finding := range findings {
+		results = append(results, analyzedResult(file, finding))
+	}
+	return results, true, nil
+}
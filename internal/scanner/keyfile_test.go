@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestScanKeyFile 测试二进制 DER 证书的解析, PEM 文本文件仍按规则扫描
+func TestScanKeyFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "scanner.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	derFile := filepath.Join(dir, "server.der")
+	pemFile := filepath.Join(dir, "server.crt")
+	if err := os.WriteFile(derFile, der, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	writeFile(t, pemFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+
+	rules := baserule.RuleMap{
+		"Keys": []baserule.Rule{{Name: "Certificate", FRegex: `-----BEGIN CERTIFICATE-----[A-Za-z0-9+/=\s]*?-----END CERTIFICATE-----`, Engine: "go", Analyzer: baserule.AnalyzerKey, Loaded: true}},
+	}
+	instance, err := NewScanner(rules, &ScanConfig{ProjectPath: dir, Workers: 1, KeyFiles: true})
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+
+	results, _, err := instance.scanFile(derFile)
+	if err != nil {
+		t.Fatalf("scanFile failed: %v", err)
+	}
+	if len(results) != 7 || results[0].Group != KeyFileGroup || results[0].Match != "server.der" || results[0].Analysis != "" {
+		t.Fatalf("unexpected key file results: %+v", results)
+	}
+	if results[3].Analysis != "cert.subject" || results[3].Match != "CN=scanner.test" || results[5].Match != "true" {
+		t.Errorf("unexpected certificate analysis: %+v", results[3:])
+	}
+
+	// PEM 编码的 .crt 文件不是 DER, 按文本文件扫描并由规则的分析器解析
+	results, _, err = instance.scanFile(pemFile)
+	if err != nil {
+		t.Fatalf("scanFile failed: %v", err)
+	}
+	if len(results) != 7 || results[0].Group != "Keys" || results[3].Match != "CN=scanner.test" {
+		t.Errorf("unexpected PEM results: %+v", results)
+	}
+
+	// 未启用时二进制文件按文本扫描, 没有结果
+	instance, _ = NewScanner(rules, &ScanConfig{ProjectPath: dir, Workers: 1})
+	if results, _, _ := instance.scanFile(derFile); len(results) != 0 {
+		t.Errorf("expected no results without key file detection, got %+v", results)
+	}
+}
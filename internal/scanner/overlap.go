@@ -0,0 +1,194 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/winezer0/xutils/logging"
+	"privacycheck/internal/baserule"
+)
+
+// 重叠结果处理方式
+const (
+	OverlapNone     = "none"     // 保留全部结果
+	OverlapPriority = "priority" // 只保留优先级最高的规则的结果
+	OverlapMerge    = "merge"    // 合并为优先级最高的结果, 并列出全部命中的规则
+)
+
+// overlapResolver 同一文件中匹配位置重叠的结果处理
+type overlapResolver struct {
+	mode string
+}
+
+// newOverlapResolver 创建重叠结果处理器, 不处理重叠时返回nil
+func newOverlapResolver(mode string) (*overlapResolver, error) {
+	switch mode {
+	case "", OverlapNone:
+		return nil, nil
+	case OverlapPriority, OverlapMerge:
+	default:
+		return nil, fmt.Errorf("unsupported overlap mode: %s", mode)
+	}
+
+	return &overlapResolver{mode: mode}, nil
+}
+
+// resultSpan 结果在文件中的字节区间, 解码后的结果为最外层编码内容中编码匹配值的部分的区间
+func resultSpan(result ScanResult) (int, int) {
+	if result.DecodeChain != "" {
+		return result.Position, result.Position + result.EncodedSize
+	}
+	return result.Position, result.Position + len(result.Match)
+}
+
+// overlaps 判断两个结果的匹配区间是否重叠
+func overlaps(a, b ScanResult) bool {
+	aStart, aEnd := resultSpan(a)
+	bStart, bEnd := resultSpan(b)
+	return aStart < bEnd && bStart < aEnd
+}
+
+// ruleLabel 返回结果所属规则的标识: 规则ID, 未配置时为 "组名: 规则名"
+func ruleLabel(result ScanResult) string {
+	if result.RuleID != "" {
+		return result.RuleID
+	}
+	return result.Group + ": " + result.RuleName
+}
+
+// better 判断结果 a 是否优先于 b
+// 依次比较: 规则优先级、严重等级、置信度、匹配长度(越短越具体), 最后按组名与规则名保证结果稳定
+// 优先级取自产生结果的规则, 同一规则组中的同名规则各自使用自己的优先级
+func (o *overlapResolver) better(a, b ScanResult) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if rankA, rankB := baserule.SeverityRank(a.Severity), baserule.SeverityRank(b.Severity); rankA != rankB {
+		return rankA > rankB
+	}
+	if rankA, rankB := baserule.ConfidenceRank(a.Confidence), baserule.ConfidenceRank(b.Confidence); rankA != rankB {
+		return rankA > rankB
+	}
+	if len(a.Match) != len(b.Match) {
+		return len(a.Match) < len(b.Match)
+	}
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.RuleName != b.RuleName {
+		return a.RuleName < b.RuleName
+	}
+	return a.Position < b.Position
+}
+
+// resolve 按文件处理重叠结果, 文件顺序保持不变, 同一文件的结果按位置排序
+// 分析器的分析结果不参与重叠比较, 跟随被分析的匹配: 匹配被保留时紧随其后输出, 被丢弃或合并时一起丢弃
+func (o *overlapResolver) resolve(results []ScanResult) []ScanResult {
+	var files []string
+	byFile := make(map[string][]ScanResult)
+	analyzed := make(map[string][]ScanResult) // 文件+被分析的匹配 -> 分析结果
+	for _, result := range results {
+		if _, ok := byFile[result.File]; !ok {
+			files = append(files, result.File)
+			byFile[result.File] = nil
+		}
+		if result.Analysis != "" {
+			key := result.File + "\x00" + parentKey(result)
+			analyzed[key] = append(analyzed[key], result)
+		} else {
+			byFile[result.File] = append(byFile[result.File], result)
+		}
+	}
+
+	resolved := make([]ScanResult, 0, len(results))
+	for _, file := range files {
+		for _, result := range o.resolveFile(byFile[file]) {
+			resolved = append(resolved, result)
+			key := file + "\x00" + parentKey(result)
+			resolved = append(resolved, analyzed[key]...)
+			delete(analyzed, key)
+		}
+	}
+
+	logging.Infof("overlap resolution (%s): %d -> %d results", o.mode, len(results), len(resolved))
+	return resolved
+}
+
+// parentKey 分析结果与被分析的匹配共有的标识: 规则(含区分同名规则的规则key)、位置与解码链
+func parentKey(result ScanResult) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%s", result.Group, result.RuleName, result.RuleKey, result.Position, result.DecodeChain)
+}
+
+// resolveFile 处理单个文件的结果: 按位置划分相互重叠的结果簇, 在簇内按优先级保留不重叠的结果
+func (o *overlapResolver) resolveFile(results []ScanResult) []ScanResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Position < results[j].Position
+	})
+
+	var resolved []ScanResult
+	for start := 0; start < len(results); {
+		_, clusterEnd := resultSpan(results[start])
+		end := start + 1
+		for end < len(results) && results[end].Position < clusterEnd {
+			_, spanEnd := resultSpan(results[end])
+			clusterEnd = max(clusterEnd, spanEnd)
+			end++
+		}
+		resolved = append(resolved, o.resolveCluster(results[start:end])...)
+		start = end
+	}
+	return resolved
+}
+
+// resolveCluster 在重叠结果簇内按优先级保留结果, 与已保留结果重叠的结果被丢弃或合并到该结果
+func (o *overlapResolver) resolveCluster(cluster []ScanResult) []ScanResult {
+	if len(cluster) == 1 {
+		return cluster
+	}
+
+	ranked := make([]ScanResult, len(cluster))
+	copy(ranked, cluster)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return o.better(ranked[i], ranked[j])
+	})
+
+	var kept []ScanResult
+	for _, result := range ranked {
+		merged := false
+		for i := range kept {
+			if !overlaps(kept[i], result) {
+				continue
+			}
+			if o.mode == OverlapMerge && ruleLabel(result) != ruleLabel(kept[i]) {
+				kept[i].MatchedRules = appendLabel(kept[i].MatchedRules, ruleLabel(result))
+			}
+			merged = true
+			break
+		}
+		if !merged {
+			kept = append(kept, result)
+		}
+	}
+
+	if o.mode == OverlapMerge {
+		for i := range kept {
+			if len(kept[i].MatchedRules) > 0 {
+				kept[i].MatchedRules = append([]string{ruleLabel(kept[i])}, kept[i].MatchedRules...)
+			}
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Position < kept[j].Position
+	})
+	return kept
+}
+
+// appendLabel 追加不重复的规则标识
+func appendLabel(labels []string, label string) []string {
+	for _, existing := range labels {
+		if existing == label {
+			return labels
+		}
+	}
+	return append(labels, label)
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"privacycheck/internal/baserule"
+)
+
+// rulePrefilter 关键字预过滤器: 对内容执行一次 Aho-Corasick 匹配, 只有包含关键字的规则才执行正则
+type rulePrefilter struct {
+	matcher      *ahoCorasick
+	ruleKeywords map[string][]int // 规则key -> 关键字序号, 不在其中的规则总是执行
+}
+
+// newRulePrefilter 根据规则的预过滤关键字(配置或自动提取)创建预过滤器, 没有规则可以预过滤时返回nil
+// 只有编译为 Go 引擎的规则才从正则中自动提取关键字
+func newRulePrefilter(keys map[string]baserule.Rule, matchers map[string]baserule.RegexMatcher) *rulePrefilter {
+	prefilter := &rulePrefilter{ruleKeywords: make(map[string][]int)}
+	var keywords []string
+	keywordIndex := make(map[string]int)
+
+	for key, rule := range keys {
+		for _, keyword := range rule.PrefilterKeywords(baserule.MatcherEngine(matchers[key])) {
+			index, ok := keywordIndex[keyword]
+			if !ok {
+				index = len(keywords)
+				keywordIndex[keyword] = index
+				keywords = append(keywords, keyword)
+			}
+			prefilter.ruleKeywords[key] = append(prefilter.ruleKeywords[key], index)
+		}
+	}
+
+	if len(keywords) == 0 {
+		return nil
+	}
+	prefilter.matcher = newAhoCorasick(keywords)
+	return prefilter
+}
+
+// prefilterHits 单次内容的关键字命中情况
+type prefilterHits struct {
+	prefilter *rulePrefilter
+	hits      []bool
+}
+
+// match 对内容执行关键字匹配
+func (p *rulePrefilter) match(content string) prefilterHits {
+	return prefilterHits{prefilter: p, hits: p.matcher.match(baserule.FoldKeyword(content))}
+}
+
+// allowed 判断规则是否需要执行正则: 未配置关键字或任一关键字命中
+func (h prefilterHits) allowed(key string) bool {
+	if h.prefilter == nil {
+		return true
+	}
+	indexes, ok := h.prefilter.ruleKeywords[key]
+	if !ok {
+		return true
+	}
+	for _, index := range indexes {
+		if h.hits[index] {
+			return true
+		}
+	}
+	return false
+}
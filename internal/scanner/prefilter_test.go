@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"privacycheck/internal/baserule"
+)
+
+// TestAhoCorasick 测试多模式关键字匹配
+func TestAhoCorasick(t *testing.T) {
+	keywords := []string{"he", "she", "his", "hers", "xyz"}
+	matcher := newAhoCorasick(keywords)
+
+	hits := matcher.match("ushers")
+	expected := []bool{true, true, false, true, false}
+	for i := range keywords {
+		if hits[i] != expected[i] {
+			t.Errorf("keyword %q hit = %v, expected %v", keywords[i], hits[i], expected[i])
+		}
+	}
+
+	if hits := matcher.match(""); hits[0] || hits[4] {
+		t.Errorf("expected no hits on empty content, got %v", hits)
+	}
+}
+
+// TestRuleEnginePrefilter 测试预过滤不改变扫描结果
+func TestRuleEnginePrefilter(t *testing.T) {
+	rules := baserule.RuleMap{
+		"Test Group": []baserule.Rule{
+			{Name: "JDBC", FRegex: `jdbc:[a-z]+://[^\s"]+`, Engine: "go", Loaded: true},
+			{Name: "Token", FRegex: `=\s*([a-z0-9]{16,})`, SecretGroup: 1, Keywords: []string{"token", "secret"}, Loaded: true},
+			{Name: "Phone", FRegex: `1[3-9]\d{9}`, Engine: "java", Loaded: true},
+		},
+	}
+	contents := []string{
+		`url = "JDBC:MySQL://db.internal:3306/app" phone 13812345678`,
+		"api_token = abcdef0123456789abcdef",
+		"value = abcdef0123456789abcdef ſecret",
+		"nothing interesting here",
+	}
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	if engine.prefilter == nil || len(engine.prefilter.ruleKeywords) != 2 {
+		t.Fatalf("expected prefilter for JDBC and Token rules")
+	}
+	baseline, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	baseline.DisablePrefilter()
+
+	for _, content := range contents {
+		actual := resultKeys(engine.ApplyRules(content, "a.txt", 0, 1))
+		expected := resultKeys(baseline.ApplyRules(content, "a.txt", 0, 1))
+		if len(actual) != len(expected) {
+			t.Errorf("content %q: prefiltered results %v, expected %v", content, actual, expected)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Errorf("content %q: prefiltered results %v, expected %v", content, actual, expected)
+				break
+			}
+		}
+	}
+
+	// 配置的关键字不在内容中时即使正则可以匹配也跳过
+	if results := engine.ApplyRules("value = abcdef0123456789abcdef", "a.txt", 0, 1); len(results) != 0 {
+		t.Errorf("expected Token rule to be skipped without keywords, got %v", results)
+	}
+}
+
+// TestRuleEnginePrefilterEmbedded 测试默认规则在关键字预过滤下的扫描结果与不预过滤时一致
+func TestRuleEnginePrefilterEmbedded(t *testing.T) {
+	rules := loadEmbeddedRules(t)
+	content := loadSensitiveSample(t)
+
+	engine, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	if engine.prefilter == nil {
+		t.Fatal("expected prefilter for embedded rules")
+	}
+	for groupName, ruleList := range rules {
+		for i, rule := range ruleList {
+			key := fmt.Sprintf("%s_%d", groupName, i)
+			_, filtered := engine.prefilter.ruleKeywords[key]
+			if filtered && len(rule.Keywords) == 0 && baserule.MatcherEngine(engine.compiledReg[key]) != baserule.RegexEngineGo {
+				t.Errorf("rule %s on the java engine should not use derived keywords", rule.Name)
+			}
+		}
+	}
+	baseline, err := NewRuleEngine(rules)
+	if err != nil {
+		t.Fatalf("NewRuleEngine failed: %v", err)
+	}
+	baseline.DisablePrefilter()
+
+	actual := resultKeys(engine.ApplyRules(content, "a.txt", 0, 1))
+	expected := resultKeys(baseline.ApplyRules(content, "a.txt", 0, 1))
+	if len(expected) == 0 {
+		t.Fatal("expected results on sensitive sample")
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("prefiltered results %v, expected %v", actual, expected)
+	}
+}
+
+// resultKeys 返回排序后的结果标识
+func resultKeys(results []ScanResult) []string {
+	var keys []string
+	for _, result := range results {
+		keys = append(keys, result.RuleName+":"+result.Match)
+	}
+	sort.Strings(keys)
+	return keys
+}
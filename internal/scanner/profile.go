@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/utils"
+)
+
+// ruleCounters 单条规则的累计计数(并发更新)
+type ruleCounters struct {
+	duration atomic.Int64 // 累计匹配耗时(纳秒)
+	bytes    atomic.Int64 // 累计扫描字节数
+	calls    atomic.Int64 // 匹配次数(文件或分块数)
+	matches  atomic.Int64 // 正则命中次数
+	results  atomic.Int64 // 通过检查后输出的结果数
+	errors   atomic.Int64 // 匹配错误次数(主要为 regexp2 超时)
+	skipped  atomic.Int64 // 被关键字预过滤跳过的次数
+}
+
+// RuleProfile 单条规则的性能统计
+type RuleProfile struct {
+	Group      string  `json:"group"`
+	RuleID     string  `json:"rule_id,omitempty"`
+	RuleName   string  `json:"rule_name"`
+	Engine     string  `json:"engine"`
+	DurationMs float64 `json:"duration_ms"` // 累计匹配耗时(毫秒)
+	Bytes      int64   `json:"bytes"`       // 累计扫描字节数
+	Calls      int64   `json:"calls"`       // 匹配次数(文件或分块数)
+	Matches    int64   `json:"matches"`     // 正则命中次数
+	Results    int64   `json:"results"`     // 输出的结果数
+	Timeouts   int64   `json:"timeouts"`    // 匹配超时(错误)次数
+	Skipped    int64   `json:"skipped"`     // 被关键字预过滤跳过的次数
+	MBPerSec   float64 `json:"mb_per_sec"`  // 平均吞吐量
+	TimeShare  float64 `json:"time_share"`  // 占全部规则耗时的比例
+}
+
+// RuleProfiler 规则性能统计器, 记录每条规则的累计耗时、扫描字节数、命中数与超时数
+type RuleProfiler struct {
+	rules    map[string]*ruleCounters // 规则key -> 计数
+	profiles map[string]RuleProfile   // 规则key -> 规则信息
+}
+
+// newRuleProfiler 创建规则性能统计器
+func newRuleProfiler() *RuleProfiler {
+	return &RuleProfiler{
+		rules:    make(map[string]*ruleCounters),
+		profiles: make(map[string]RuleProfile),
+	}
+}
+
+// register 登记规则, 需在扫描开始前完成(扫描期间map只读)
+func (p *RuleProfiler) register(key string, profile RuleProfile) {
+	p.rules[key] = &ruleCounters{}
+	p.profiles[key] = profile
+}
+
+// record 记录一次规则匹配
+func (p *RuleProfiler) record(key string, elapsed time.Duration, bytes, matches, results int, err error) {
+	counters, ok := p.rules[key]
+	if !ok {
+		return
+	}
+	counters.duration.Add(int64(elapsed))
+	counters.bytes.Add(int64(bytes))
+	counters.calls.Add(1)
+	counters.matches.Add(int64(matches))
+	counters.results.Add(int64(results))
+	if err != nil {
+		counters.errors.Add(1)
+	}
+}
+
+// skip 记录一次被关键字预过滤跳过的匹配
+func (p *RuleProfiler) skip(key string) {
+	if counters, ok := p.rules[key]; ok {
+		counters.skipped.Add(1)
+	}
+}
+
+// Profiles 返回按累计耗时降序排列的规则统计
+func (p *RuleProfiler) Profiles() []RuleProfile {
+	var total int64
+	for _, counters := range p.rules {
+		total += counters.duration.Load()
+	}
+
+	profiles := make([]RuleProfile, 0, len(p.rules))
+	for key, counters := range p.rules {
+		profile := p.profiles[key]
+		duration := time.Duration(counters.duration.Load())
+		profile.DurationMs = float64(duration) / float64(time.Millisecond)
+		profile.Bytes = counters.bytes.Load()
+		profile.Calls = counters.calls.Load()
+		profile.Matches = counters.matches.Load()
+		profile.Results = counters.results.Load()
+		profile.Timeouts = counters.errors.Load()
+		profile.Skipped = counters.skipped.Load()
+		if duration > 0 {
+			profile.MBPerSec = float64(profile.Bytes) / 1024 / 1024 / duration.Seconds()
+		}
+		if total > 0 {
+			profile.TimeShare = float64(duration) / float64(total)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].DurationMs != profiles[j].DurationMs {
+			return profiles[i].DurationMs > profiles[j].DurationMs
+		}
+		return profiles[i].Group+profiles[i].RuleName < profiles[j].Group+profiles[j].RuleName
+	})
+	return profiles
+}
+
+// LogSummary 输出耗时最长的 top 条规则
+func (p *RuleProfiler) LogSummary(top int) {
+	profiles := p.Profiles()
+	if top > 0 && len(profiles) > top {
+		profiles = profiles[:top]
+	}
+
+	logging.Infof("rule profile: top %d slowest rules", len(profiles))
+	for i, profile := range profiles {
+		logging.Infof("%2d. [%s: %s] engine: %s, time: %.2fms (%.1f%%), scanned: %d bytes, %.2f MB/s, matches: %d, results: %d, timeouts: %d, skipped: %d",
+			i+1, profile.Group, profile.RuleName, profile.Engine, profile.DurationMs, profile.TimeShare*100,
+			profile.Bytes, profile.MBPerSec, profile.Matches, profile.Results, profile.Timeouts, profile.Skipped)
+	}
+}
+
+// SaveJSON 将全部规则统计保存为JSON文件
+func (p *RuleProfiler) SaveJSON(profileFile string) error {
+	return utils.SaveJSON(profileFile, p.Profiles())
+}
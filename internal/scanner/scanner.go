@@ -1,8 +1,9 @@
 package scanner
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"github.com/winezer0/xutils/cacher"
 	"github.com/winezer0/xutils/logging"
 	"github.com/winezer0/xutils/progress"
 	"github.com/winezer0/xutils/utils"
@@ -12,10 +13,19 @@ import (
 
 // Scanner 扫描器
 type Scanner struct {
-	workers      int
-	chunkLimit   int
-	engine       *RuleEngine
-	cacheManager *cacher.CacheManager
+	workers     int
+	chunkLimit  int
+	engine      *RuleEngine
+	cache       *ResultCache // 扫描结果缓存, 为nil时不缓存
+	fingerprint string       // 规则指纹, 用于判断缓存与扫描日志是否可用
+	projectPath string
+	journalFile string // 扫描进度日志路径, 为空时不记录
+	resume      bool
+	overlap     *overlapResolver // 重叠结果处理器, 为nil时保留全部结果
+	decoder     *decoder         // 编码内容解码器, 为nil时不解码
+	keyFiles    bool             // 是否解析二进制的 DER 证书/私钥与 PKCS#12 文件
+	profileFile string
+	profileTop  int
 }
 
 // NewScanner 创建新的扫描器
@@ -26,16 +36,62 @@ func NewScanner(rules baserule.RuleMap, config *ScanConfig) (*Scanner, error) {
 	}
 
 	scanner := &Scanner{
-		engine:       engine,
-		workers:      config.Workers,
-		chunkLimit:   config.ChunkLimit,
-		cacheManager: cacher.NewCacheManager(config.CacheFile),
+		engine:      engine,
+		workers:     config.Workers,
+		chunkLimit:  config.ChunkLimit,
+		profileFile: config.ProfileFile,
+		profileTop:  config.ProfileTop,
+		projectPath: config.ProjectPath,
+		journalFile: config.JournalFile,
+		resume:      config.Resume,
+		decoder:     newDecoder(config.Decode),
+		keyFiles:    config.KeyFiles,
+	}
+
+	if scanner.overlap, err = newOverlapResolver(config.Overlap); err != nil {
+		return nil, err
+	}
+	if config.CacheFile != "" || config.JournalFile != "" {
+		scanner.fingerprint, err = RulesFingerprint(rules, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute rules fingerprint: %w", err)
+		}
+	}
+	if config.CacheFile != "" {
+		scanner.cache = NewResultCache(config.CacheFile, scanner.fingerprint)
+		scanner.cache.setPathDependence(rules, config.KeyFiles)
+	}
+	if len(config.JWTWordlist) > 0 {
+		engine.SetAnalyzerWordlist(config.JWTWordlist)
+	}
+	if config.NoPrefilter {
+		engine.DisablePrefilter()
+	}
+	if config.CombineRegex {
+		engine.EnableCombinedRegex()
+	}
+	if config.Profile || config.ProfileFile != "" {
+		engine.EnableProfiling()
+	}
+	if scanner.decoder != nil {
+		logging.Infof("decode-and-rescan enabled: max depth %d, max encoded size %d bytes", scanner.decoder.depth, scanner.decoder.maxSize)
 	}
 
 	return scanner, nil
 }
 
 func (s *Scanner) Scan(filePaths []string) ([]ScanResult, error) {
+	var allResults []ScanResult
+	var journal *ScanJournal
+	if s.journalFile != "" {
+		var err error
+		journal, err = OpenJournal(s.journalFile, s.fingerprint, s.projectPath, s.resume)
+		if err != nil {
+			return nil, err
+		}
+		filePaths, allResults = s.resumeCompleted(journal, filePaths)
+	}
+
 	logging.Infof("starting scan files: %d worker: %d", len(filePaths), s.workers)
 	bar := progress.NewProcessBarByTotalTask(int64(len(filePaths)), "Scanning ...")
 	scanJobs := make(chan string, 100)
@@ -61,7 +117,6 @@ func (s *Scanner) Scan(filePaths []string) ([]ScanResult, error) {
 		// 此处不再关闭 scanResults！
 	}()
 
-	var allResults []ScanResult
 	var resultsMux sync.Mutex
 
 	// 收集 exactly N 个结果
@@ -74,63 +129,222 @@ func (s *Scanner) Scan(filePaths []string) ([]ScanResult, error) {
 			resultsMux.Lock()
 			allResults = append(allResults, job.Results...)
 			resultsMux.Unlock()
+			if journal != nil {
+				if err := journal.Record(job.FilePath, job.state, job.Results); err != nil {
+					logging.Warnf("failed to record %s in scan journal: %v", job.FilePath, err)
+				}
+			}
+		}
+	}
+
+	if journal != nil {
+		if err := journal.Close(); err != nil {
+			logging.Warnf("failed to close scan journal %s: %v", s.journalFile, err)
 		}
 	}
 
-	s.cacheManager.Clear()
+	s.saveCache()
+	s.reportProfile()
+
+	// 处理同一文件中匹配位置重叠的结果
+	if s.overlap != nil {
+		allResults = s.overlap.resolve(allResults)
+	}
 	return allResults, nil
 }
 
+// FinishJournal 扫描结果成功输出后删除扫描日志(仅在启用时), 输出失败时保留日志以便 --resume 恢复
+func (s *Scanner) FinishJournal() {
+	if s.journalFile == "" {
+		return
+	}
+	if err := RemoveJournal(s.journalFile); err != nil {
+		logging.Warnf("failed to remove scan journal %s: %v", s.journalFile, err)
+	}
+}
+
+// resumeCompleted 从扫描日志中取出之前已完成文件的结果, 返回仍需扫描的文件
+func (s *Scanner) resumeCompleted(journal *ScanJournal, filePaths []string) ([]string, []ScanResult) {
+	if journal.CompletedCount() == 0 {
+		return filePaths, nil
+	}
+
+	var pending []string
+	var results []ScanResult
+	resumed := 0
+	for _, filePath := range filePaths {
+		completed, state, ok := journal.Completed(filePath)
+		if !ok {
+			pending = append(pending, filePath)
+			continue
+		}
+		resumed++
+		results = append(results, completed...)
+		if s.cache != nil {
+			s.cache.Set(filePath, state, completed)
+		}
+	}
+	logging.Infof("resuming scan from journal %s: %d/%d files already completed, %d results restored", s.journalFile, resumed, len(filePaths), len(results))
+	return pending, results
+}
+
+// saveCache 输出缓存命中统计并保存缓存文件(仅在启用时)
+func (s *Scanner) saveCache() {
+	if s.cache == nil {
+		return
+	}
+
+	s.cache.LogStats()
+	if err := s.cache.Save(); err != nil {
+		logging.Errorf("failed to save scan cache %s: %v", s.cache.path, err)
+		return
+	}
+	logging.Infof("scan cache has been saved to: %s", s.cache.path)
+}
+
+// reportProfile 输出规则性能统计(仅在启用时)
+func (s *Scanner) reportProfile() {
+	profiler := s.engine.Profiler()
+	if profiler == nil {
+		return
+	}
+
+	profiler.LogSummary(s.profileTop)
+	if s.profileFile != "" {
+		if err := profiler.SaveJSON(s.profileFile); err != nil {
+			logging.Errorf("failed to save rule profile %s: %v", s.profileFile, err)
+			return
+		}
+		logging.Infof("rule profile has been saved to: %s", s.profileFile)
+	}
+}
+
 // worker 工作协程 - 直接处理文件路径
 func (s *Scanner) worker(jobs <-chan string, results chan<- ScanJob, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for filePath := range jobs {
 		// 执行扫描
 		job := ScanJob{FilePath: filePath}
-		job.Results, job.Error = s.scanFile(filePath)
+		job.Results, job.state, job.Error = s.scanFile(filePath)
 		results <- job
 	}
 }
 
 // scanFile 扫描单个文件 - 直接接受文件路径
-func (s *Scanner) scanFile(filePath string) ([]ScanResult, error) {
-	// 检查缓存
-	var cachedResults []ScanResult
-	if ok := s.cacheManager.GetAs(filePath, &cachedResults); ok {
-		return cachedResults, nil
+// 返回读取文件前获取的文件状态(含扫描内容的哈希), 供缓存与扫描日志记录
+func (s *Scanner) scanFile(filePath string) ([]ScanResult, fileState, error) {
+	// 在读取内容前获取文件状态, 扫描期间文件被修改时缓存与扫描日志记录的状态与新文件不一致
+	state, err := statFile(filePath)
+	if err != nil {
+		return nil, state, fmt.Errorf("failed to get file info %s: %w", filePath, err)
+	}
+	reader := &fileReader{filePath: filePath, chunkLimit: s.chunkLimit}
+
+	// 检查缓存, 比较内容哈希时读取的内容直接用于扫描
+	if s.cache != nil {
+		if cachedResults, hash, ok := s.cache.Get(filePath, state, reader.hash); ok {
+			state.Hash = hash
+			return s.engine.AnalyzeResults(cachedResults), state, nil
+		}
 	}
 
-	// 存储扫描结果
+	// 二进制密钥文件不按文本扫描
+	if s.keyFiles {
+		results, ok, err := s.scanKeyFile(filePath)
+		if err != nil {
+			return nil, state, fmt.Errorf("failed to read the key file %s error: %w", filePath, err)
+		}
+		if ok {
+			return results, state, nil
+		}
+	}
+
+	// 存储扫描结果, 同时计算扫描内容的哈希
 	var results []ScanResult
+	hash := sha256.New()
+	err = reader.each(func(content string, positionOffset int, startLineNumber int) {
+		hash.Write([]byte(content))
+		results = append(results, s.applyRules(content, filePath, positionOffset, startLineNumber)...)
+	})
+	if err != nil {
+		return nil, state, err
+	}
+	state.Hash = hex.EncodeToString(hash.Sum(nil))
+
+	// 更新缓存
+	if s.cache != nil {
+		s.cache.Set(filePath, state, results)
+	}
+	return results, state, nil
+}
+
+// fileReader 读取待扫描文件的内容, 缓存比较哈希与扫描共用
+// 未分块读取的内容只读取一次; 超过分块阈值的文件每次逐块读取, 不在内存中保留
+type fileReader struct {
+	filePath   string
+	chunkLimit int
+	info       *utils.FileInfo // 文件大小与编码, 首次读取时获取
+	content    *string         // 未分块读取时已读取的内容
+}
+
+// each 按扫描时的方式依次回调文件内容: 超过分块阈值时逐块回调, 否则回调一次全部内容
+func (r *fileReader) each(fn func(content string, positionOffset int, startLineNumber int)) error {
+	if r.content != nil {
+		fn(*r.content, 0, 1)
+		return nil
+	}
+
 	// 获取文件大小和编码信息
-	fileInfo, err := utils.PathToFileInfo(filePath)
-	if err != nil || fileInfo.Size == 0 {
-		return nil, fmt.Errorf("failed to get file info %s: %w", filePath, err)
+	if r.info == nil {
+		fileInfo, err := utils.PathToFileInfo(r.filePath)
+		if err != nil || fileInfo.Size == 0 {
+			return fmt.Errorf("failed to get file info %s: %w", r.filePath, err)
+		}
+		r.info = fileInfo
 	}
 
 	// 判断是否启用分块读取以及文件大小是否超过阈值
-	chunkThreshold := int64(s.chunkLimit) * 1024 * 1024 // 转换为字节
-	if s.chunkLimit > 0 && fileInfo.Size > chunkThreshold {
+	chunkThreshold := int64(r.chunkLimit) * 1024 * 1024 // 转换为字节
+	if r.chunkLimit > 0 && r.info.Size > chunkThreshold {
 		const chunkSize = 1024 * 1024 // 1MB per chunk
-		err := utils.ReadFileByChunk(filePath, fileInfo.Encoding, chunkSize, func(chunk utils.ChunkInfo) error {
-			// 对每个块应用规则，传入正确的行号偏移
-			chunkResults := s.engine.ApplyRules(chunk.Content, filePath, int(chunk.StartOffset), chunk.StartLine)
-			results = append(results, chunkResults...)
+		err := utils.ReadFileByChunk(r.filePath, r.info.Encoding, chunkSize, func(chunk utils.ChunkInfo) error {
+			// 传入正确的位置与行号偏移
+			fn(chunk.Content, int(chunk.StartOffset), chunk.StartLine)
 			return nil
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to read the large file %s error: %w", filePath, err)
+			return fmt.Errorf("failed to read the large file %s error: %w", r.filePath, err)
 		}
-	} else {
-		// 小文件或禁用分块读取时，直接读取全部内容
-		content, err := utils.ReadFileWithEncoding(filePath, fileInfo.Encoding)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read the file %s error: %w", filePath, err)
-		}
-		results = s.engine.ApplyRules(content, filePath, 0, 1)
+		return nil
 	}
 
-	// 更新缓存
-	s.cacheManager.Set(filePath, results)
-	return results, nil
+	// 小文件或禁用分块读取时，直接读取全部内容
+	content, err := utils.ReadFileWithEncoding(r.filePath, r.info.Encoding)
+	if err != nil {
+		return fmt.Errorf("failed to read the file %s error: %w", r.filePath, err)
+	}
+	r.content = &content
+	fn(content, 0, 1)
+	return nil
+}
+
+// hash 计算扫描内容的 SHA-256
+func (r *fileReader) hash() (string, error) {
+	hash := sha256.New()
+	err := r.each(func(content string, _ int, _ int) {
+		hash.Write([]byte(content))
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// applyRules 对内容应用所有规则, 启用解码时追加解码编码内容后重新扫描的结果
+func (s *Scanner) applyRules(content, filePath string, positionOffset int, startLineNumber int) []ScanResult {
+	results := s.engine.ApplyRules(content, filePath, positionOffset, startLineNumber)
+	if s.decoder != nil {
+		results = append(results, s.decoder.rescan(s.engine, content, results, filePath, positionOffset, startLineNumber)...)
+	}
+	return results
 }
@@ -5,8 +5,23 @@ type ScanConfig struct {
 	ProjectName string
 	ProjectPath string
 	CacheFile   string
-	ChunkLimit  int // 分块读取阈值，单位MB
+	JournalFile string // 扫描进度日志路径(为空则不记录)
+	Resume      bool   // 从扫描进度日志恢复被中断的扫描
+	ChunkLimit  int    // 分块读取阈值，单位MB
 	Workers     int
+
+	NoPrefilter  bool // 关闭关键字预过滤
+	CombineRegex bool // 合并 Go 引擎规则的正则, 一次遍历找出候选规则
+
+	Overlap string         // 同一文件中匹配位置重叠的结果处理方式(none/priority/merge)
+	Decode  *DecodeOptions // 解码 base64/url/hex 编码内容后重新扫描, 为nil时不解码
+
+	JWTWordlist []string // JWT 分析器额外使用的弱签名密钥字典
+	KeyFiles    bool     // 解析二进制的 DER 证书/私钥与 PKCS#12 文件
+
+	Profile     bool   // 是否记录每条规则的性能统计
+	ProfileFile string // 规则性能统计JSON输出路径(为空则仅输出日志)
+	ProfileTop  int    // 日志中展示的最慢规则数量
 }
 
 // ScanJob 扫描任务结果
@@ -14,16 +29,62 @@ type ScanJob struct {
 	FilePath string
 	Results  []ScanResult
 	Error    error
+	state    fileState // 读取文件前获取的文件状态
 }
 
 // ScanResult 表示扫描结果
 type ScanResult struct {
-	File       string `json:"cacheFile"`   // 文件路径
-	Group      string `json:"group"`       // 规则组名称
-	RuleName   string `json:"rule_name"`   // 规则名称
-	Match      string `json:"match"`       // 匹配的内容
-	Context    string `json:"context"`     // 上下文内容
-	Position   int    `json:"position"`    // 匹配位置
-	LineNumber int    `json:"line_number"` // 行号
-	Sensitive  bool   `json:"sensitive"`   // 是否敏感信息
+	File       string   `json:"file"`               // 文件路径
+	Group      string   `json:"group"`              // 规则组名称
+	RuleID     string   `json:"rule_id,omitempty"`  // 规则ID
+	RuleName   string   `json:"rule_name"`          // 规则名称
+	Match      string   `json:"match"`              // 匹配的内容
+	Context    string   `json:"context"`            // 上下文内容
+	Position   int      `json:"position"`           // 匹配位置
+	LineNumber int      `json:"line_number"`        // 行号
+	Sensitive  bool     `json:"sensitive"`          // 是否敏感信息
+	Severity   string   `json:"severity"`           // 严重等级
+	Confidence string   `json:"confidence"`         // 置信度
+	Category   string   `json:"category,omitempty"` // 数据分类
+	Tags       []string `json:"tags,omitempty"`     // 规则标签
+	Entropy    float64  `json:"entropy,omitempty"`  // 提取值的香农熵(仅熵检测规则)
+
+	MatchedRules []string `json:"matched_rules,omitempty"` // 合并重叠结果时命中同一位置的全部规则(按优先级排序)
+	DecodeChain  string   `json:"decode_chain,omitempty"`  // 解码链(如 base64>url), 结果位置为匹配值在最外层编码内容中对应区间的位置
+	Encoded      string   `json:"encoded,omitempty"`       // 最外层编码内容中编码匹配值的部分(过长时截断)
+	EncodedSize  int      `json:"encoded_size,omitempty"`  // 编码匹配值的部分的长度(字节), 与位置一起构成结果在文件中的区间
+	Analysis     string   `json:"analysis,omitempty"`      // 规则分析器的分析结果类型(如 jwt.algorithm), 为空时为正则匹配结果
+
+	Description string   `json:"description,omitempty"` // 规则描述
+	Remediation string   `json:"remediation,omitempty"` // 修复建议
+	References  []string `json:"references,omitempty"`  // 参考链接
+
+	RuleKey  string `json:"-"` // 产生结果的规则key(组名_序号), 区分同一规则组中的同名规则
+	Priority int    `json:"-"` // 规则的重叠结果处理优先级
+}
+
+// resultRule 结果中不输出的规则信息, 缓存与扫描日志按结果顺序单独保存
+type resultRule struct {
+	Key      string `json:"key"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// resultRules 返回结果中不输出的规则信息, 与结果一一对应
+func resultRules(results []ScanResult) []resultRule {
+	rules := make([]resultRule, len(results))
+	for i, result := range results {
+		rules[i] = resultRule{Key: result.RuleKey, Priority: result.Priority}
+	}
+	return rules
+}
+
+// restoreResultRules 恢复从缓存或扫描日志读取的结果的规则信息, 数量不一致时不恢复
+func restoreResultRules(results []ScanResult, rules []resultRule) {
+	if len(rules) != len(results) {
+		return
+	}
+	for i, rule := range rules {
+		results[i].RuleKey = rule.Key
+		results[i].Priority = rule.Priority
+	}
 }